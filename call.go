@@ -0,0 +1,111 @@
+package zenrpc
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+)
+
+// Call is a single in-process call for Server.CallBatch.
+type Call struct {
+	// Method is full method name with namespace, e.g. arith.multiply.
+	Method string
+
+	// Args are method params: struct or map for named params, slice for positional params. Could be nil.
+	Args interface{}
+
+	// Result is pointer to value for unmarshalling result. Could be nil if result is not needed.
+	Result interface{}
+
+	// Error is set if call failed.
+	Error *Error
+}
+
+// Call invokes registered method in-process with full middleware chain.
+// Args are marshalled to params and result is unmarshalled to given pointer.
+// Returned error is always *Error.
+func (s Server) Call(ctx context.Context, method string, args, result interface{}) error {
+	return s.CallBatch(ctx, &Call{Method: method, Args: args, Result: result})
+}
+
+// CallBatch invokes given calls in-process asynchronously like batch request and sets results and errors for each call.
+// Returned error is first failed call error.
+func (s Server) CallBatch(ctx context.Context, calls ...*Call) error {
+	requests := make([]Request, 0, len(calls))
+	for i, c := range calls {
+		req, err := newCallRequest(i, c)
+		if err != nil {
+			c.Error = err
+			continue
+		}
+
+		requests = append(requests, req)
+	}
+
+	// processing requests
+	var responses []Response
+	if len(requests) == 1 {
+		responses = []Response{s.processRequest(ctx, requests[0])}
+	} else if len(requests) > 1 {
+		responses = s.processBatch(ctx, requests)
+	}
+
+	// linking responses with calls by id
+	for _, resp := range responses {
+		if resp.ID == nil {
+			continue
+		}
+
+		i, err := strconv.Atoi(string(*resp.ID))
+		if err != nil || i < 0 || i >= len(calls) {
+			continue
+		}
+
+		calls[i].setResponse(resp)
+	}
+
+	for _, c := range calls {
+		if c.Error != nil {
+			return c.Error
+		}
+	}
+
+	return nil
+}
+
+// newCallRequest creates JSON-RPC 2.0 Request for call with index as ID.
+func newCallRequest(i int, c *Call) (Request, *Error) {
+	id := json.RawMessage(strconv.Itoa(i))
+	req := Request{
+		Version: Version,
+		ID:      &id,
+		Method:  c.Method,
+	}
+
+	if c.Args != nil {
+		params, err := json.Marshal(c.Args)
+		if err != nil {
+			return req, NewError(InvalidParams, err)
+		}
+
+		req.Params = params
+	}
+
+	return req, nil
+}
+
+// setResponse sets call result or error from response.
+func (c *Call) setResponse(resp Response) {
+	if resp.Error != nil {
+		c.Error = resp.Error
+		return
+	}
+
+	if c.Result == nil || resp.Result == nil {
+		return
+	}
+
+	if err := json.Unmarshal(*resp.Result, c.Result); err != nil {
+		c.Error = NewError(ParseError, err)
+	}
+}
@@ -0,0 +1,124 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/semrush/zenrpc/v2"
+)
+
+// Batch is a builder for JSON-RPC 2.0 batch request.
+type Batch struct {
+	client  *Client
+	entries []batchEntry
+}
+
+// batchEntry is a single call or notification in batch.
+type batchEntry struct {
+	call   *zenrpc.Call
+	notify bool
+}
+
+// NewBatch returns new empty batch.
+func (c *Client) NewBatch() *Batch {
+	return &Batch{client: c}
+}
+
+// Call adds call to batch. Result and error are set to returned call after Send.
+func (b *Batch) Call(method string, params, result interface{}) *zenrpc.Call {
+	call := &zenrpc.Call{Method: method, Args: params, Result: result}
+	b.add(call, false)
+
+	return call
+}
+
+// Notify adds notification to batch.
+func (b *Batch) Notify(method string, params interface{}) {
+	b.add(&zenrpc.Call{Method: method, Args: params}, true)
+}
+
+// Len returns quantity of calls and notifications in batch.
+func (b *Batch) Len() int {
+	return len(b.entries)
+}
+
+// add adds call to batch.
+func (b *Batch) add(call *zenrpc.Call, notify bool) {
+	b.entries = append(b.entries, batchEntry{call: call, notify: notify})
+}
+
+// Send sends batch request and sets results and errors for each call. Responses are linked to calls by ID.
+// Batch is retried on transport errors only if all methods in batch are idempotent.
+// Returned error is transport error or first failed call error.
+func (b *Batch) Send(ctx context.Context) error {
+	if len(b.entries) == 0 {
+		return nil
+	}
+
+	idempotent := true
+	requests := make([]zenrpc.Request, 0, len(b.entries))
+	ids := make(map[string]*zenrpc.Call, len(b.entries))
+	for _, e := range b.entries {
+		req, err := b.client.newRequest(e.call.Method, e.call.Args, !e.notify)
+		if err != nil {
+			return err
+		}
+
+		requests = append(requests, req)
+		idempotent = idempotent && b.client.isIdempotent(e.call.Method)
+		if req.ID != nil {
+			ids[string(*req.ID)] = e.call
+		}
+	}
+
+	data, err := b.client.send(ctx, requests, idempotent)
+	if err != nil {
+		return err
+	}
+
+	var responses []zenrpc.Response
+	if zenrpc.IsArray(data) {
+		if err := json.Unmarshal(data, &responses); err != nil {
+			return err
+		}
+	} else if !isEmpty(data) {
+		// whole batch failed, e.g. parse error or too many requests in batch
+		var resp zenrpc.Response
+		if err := json.Unmarshal(data, &resp); err != nil {
+			return err
+		}
+
+		for _, call := range ids {
+			call.Error = resp.Error
+		}
+	}
+
+	// linking responses with calls by id
+	for _, resp := range responses {
+		if resp.ID == nil {
+			continue
+		}
+
+		if call, ok := ids[string(*resp.ID)]; ok {
+			if err := setResult(resp, call.Result); err != nil {
+				call.Error = toError(err)
+			}
+			delete(ids, string(*resp.ID))
+		}
+	}
+
+	// calls without responses
+	for _, call := range ids {
+		if call.Error == nil {
+			call.Error = zenrpc.NewError(zenrpc.InternalError, ErrEmptyResponse)
+		}
+	}
+
+	for _, e := range b.entries {
+		if e.call.Error != nil {
+			return e.call.Error
+		}
+	}
+
+	return nil
+}
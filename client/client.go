@@ -0,0 +1,216 @@
+// Package client implements JSON-RPC 2.0 client for zenrpc servers.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/semrush/zenrpc/v2"
+)
+
+const (
+	// defaultBackoff is initial delay before retry for default exponential backoff.
+	defaultBackoff = 100 * time.Millisecond
+
+	// defaultMaxBackoff is max delay before retry for default exponential backoff.
+	defaultMaxBackoff = 5 * time.Second
+)
+
+// ErrEmptyResponse is returned if server sends empty response for request with ID.
+var ErrEmptyResponse = errors.New("empty response")
+
+// lastID is last request ID. It's shared between clients, so several clients could use same multiplexed transport.
+var lastID uint64
+
+// Transport sends JSON-RPC 2.0 message to server and returns raw response.
+// Response could be empty if message contains only notifications.
+type Transport interface {
+	Send(ctx context.Context, message []byte) ([]byte, error)
+}
+
+// BackoffFunc returns delay before retry attempt. Attempt starts from 0.
+type BackoffFunc func(attempt int) time.Duration
+
+// Options is options for JSON-RPC 2.0 Client.
+type Options struct {
+	// Retries sets maximum quantity of retries on transport errors. Only idempotent methods are retried.
+	Retries int
+
+	// Backoff sets delay between retries. If nil, exponential backoff will be used.
+	Backoff BackoffFunc
+
+	// IdempotentMethods is list of methods with namespace which are safe for retry, e.g. arith.multiply.
+	IdempotentMethods []string
+}
+
+// Client is JSON-RPC 2.0 client.
+type Client struct {
+	transport  Transport
+	options    Options
+	idempotent map[string]struct{}
+}
+
+// NewClient returns new JSON-RPC 2.0 client with given transport.
+func NewClient(transport Transport, opts Options) *Client {
+	if opts.Backoff == nil {
+		opts.Backoff = ExponentialBackoff(defaultBackoff, defaultMaxBackoff)
+	}
+
+	idempotent := make(map[string]struct{}, len(opts.IdempotentMethods))
+	for _, m := range opts.IdempotentMethods {
+		idempotent[strings.ToLower(m)] = struct{}{}
+	}
+
+	return &Client{
+		transport:  transport,
+		options:    opts,
+		idempotent: idempotent,
+	}
+}
+
+// ExponentialBackoff returns backoff with delay doubled on each attempt and limited by max.
+func ExponentialBackoff(min, max time.Duration) BackoffFunc {
+	return func(attempt int) time.Duration {
+		d := min
+		for i := 0; i < attempt && d < max; i++ {
+			d *= 2
+		}
+
+		if d > max {
+			return max
+		}
+
+		return d
+	}
+}
+
+// Call invokes remote method and unmarshals result to given pointer.
+// Params could be struct or map for named params, slice for positional params or nil.
+// Errors from server are returned as *zenrpc.Error, other errors are transport or encoding errors.
+func (c *Client) Call(ctx context.Context, method string, params, result interface{}) error {
+	req, err := c.newRequest(method, params, true)
+	if err != nil {
+		return err
+	}
+
+	data, err := c.send(ctx, req, c.isIdempotent(method))
+	if err != nil {
+		return err
+	} else if isEmpty(data) {
+		return ErrEmptyResponse
+	}
+
+	var resp zenrpc.Response
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return err
+	}
+
+	return setResult(resp, result)
+}
+
+// Notify sends notification to remote method. Server doesn't respond to notifications.
+func (c *Client) Notify(ctx context.Context, method string, params interface{}) error {
+	req, err := c.newRequest(method, params, false)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.send(ctx, req, c.isIdempotent(method))
+	return err
+}
+
+// CallBatch invokes given calls in single batch request and sets results and errors for each call.
+// Responses are linked to calls by ID. Returned error is transport error or first failed call error.
+func (c *Client) CallBatch(ctx context.Context, calls ...*zenrpc.Call) error {
+	b := c.NewBatch()
+	for _, call := range calls {
+		b.add(call, false)
+	}
+
+	return b.Send(ctx)
+}
+
+// newRequest creates JSON-RPC 2.0 Request with next ID if withID is true.
+func (c *Client) newRequest(method string, params interface{}, withID bool) (zenrpc.Request, error) {
+	req := zenrpc.Request{
+		Version: zenrpc.Version,
+		Method:  method,
+	}
+
+	if withID {
+		id := json.RawMessage(strconv.FormatUint(atomic.AddUint64(&lastID, 1), 10))
+		req.ID = &id
+	}
+
+	if params != nil {
+		b, err := json.Marshal(params)
+		if err != nil {
+			return req, err
+		}
+
+		req.Params = b
+	}
+
+	return req, nil
+}
+
+// send marshals request(s) and sends it via transport. Transport errors are retried if idempotent is true.
+func (c *Client) send(ctx context.Context, v interface{}, idempotent bool) ([]byte, error) {
+	message, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	for attempt := 0; ; attempt++ {
+		data, err := c.transport.Send(ctx, message)
+		if err == nil || !idempotent || attempt >= c.options.Retries {
+			return data, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(c.options.Backoff(attempt)):
+		}
+	}
+}
+
+// isIdempotent checks if method is safe for retry.
+func (c *Client) isIdempotent(method string) bool {
+	_, ok := c.idempotent[strings.ToLower(method)]
+	return ok
+}
+
+// setResult returns response error or unmarshals response result to given pointer.
+func setResult(resp zenrpc.Response, result interface{}) error {
+	if resp.Error != nil {
+		return resp.Error
+	}
+
+	if result == nil || resp.Result == nil {
+		return nil
+	}
+
+	return json.Unmarshal(*resp.Result, result)
+}
+
+// toError converts result error to *zenrpc.Error. Non JSON-RPC errors are result decoding errors.
+func toError(err error) *zenrpc.Error {
+	if e, ok := err.(*zenrpc.Error); ok {
+		return e
+	}
+
+	return zenrpc.NewError(zenrpc.ParseError, err)
+}
+
+// isEmpty checks if response is empty. Server responds with null via WebSocket if all requests are notifications.
+func isEmpty(data []byte) bool {
+	data = bytes.TrimSpace(data)
+	return len(data) == 0 || bytes.Equal(data, []byte("null"))
+}
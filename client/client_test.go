@@ -0,0 +1,448 @@
+package client_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/semrush/zenrpc/v2"
+	"github.com/semrush/zenrpc/v2/client"
+	"github.com/semrush/zenrpc/v2/testdata"
+)
+
+func newTestServer() *httptest.Server {
+	rpc := zenrpc.NewServer(zenrpc.Options{BatchMaxLen: 4, AllowCORS: true})
+	rpc.Register("arith", &testdata.ArithService{})
+	rpc.Register("phonebook", &testdata.PhoneBook{DB: map[uint64]*testdata.Person{}})
+	rpc.Register("catalogue", &testdata.CatalogueService{})
+	rpc.Register("contacts", testdata.NewContactsInvoker(testdata.ContactList{{ID: 1, FirstName: "John"}}))
+
+	mux := http.NewServeMux()
+	mux.Handle("/", rpc)
+	mux.HandleFunc("/ws", rpc.ServeWS)
+
+	return httptest.NewServer(mux)
+}
+
+func TestClient_Call(t *testing.T) {
+	ts := newTestServer()
+	defer ts.Close()
+
+	ws, err := client.DialWS(context.Background(), "ws"+strings.TrimPrefix(ts.URL, "http")+"/ws", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+
+	for _, tr := range []client.Transport{client.NewHTTPTransport(ts.URL), ws} {
+		c := client.NewClient(tr, client.Options{})
+
+		var res int
+		if err := c.Call(context.Background(), "arith.multiply", []int{3, 2}, &res); err != nil {
+			t.Fatal(err)
+		} else if res != 6 {
+			t.Errorf("got %d expected 6", res)
+		}
+
+		err := c.Call(context.Background(), "arith.divide", map[string]int{"a": 1, "b": 1}, nil)
+		if e, ok := err.(*zenrpc.Error); !ok || e.Code != 401 || e.Message != "we do not serve 1" {
+			t.Errorf("got %#v expected 401 error", err)
+		}
+	}
+}
+
+func TestClient_CallBatch(t *testing.T) {
+	ts := newTestServer()
+	defer ts.Close()
+
+	c := client.NewClient(client.NewHTTPTransport(ts.URL), client.Options{})
+
+	var mul int
+	var quo testdata.Quotient
+	calls := []*zenrpc.Call{
+		{Method: "arith.multiply", Args: []int{3, 3}, Result: &mul},
+		{Method: "arith.divide", Args: []int{7, 2}, Result: &quo},
+		{Method: "arith.unknown"},
+	}
+
+	err := c.CallBatch(context.Background(), calls...)
+	if e, ok := err.(*zenrpc.Error); !ok || e.Code != zenrpc.MethodNotFound {
+		t.Errorf("got %#v expected method not found error", err)
+	}
+
+	if mul != 9 || quo.Quo != 3 || quo.Rem != 1 {
+		t.Errorf("got mul=%d quo=%+v", mul, quo)
+	}
+
+	// too many requests in batch
+	err = c.CallBatch(context.Background(), calls[0], calls[0], calls[0], calls[0], calls[0])
+	if e, ok := err.(*zenrpc.Error); !ok || e.Code != zenrpc.InvalidRequest {
+		t.Errorf("got %#v expected invalid request error", err)
+	}
+}
+
+func TestGeneratedClient(t *testing.T) {
+	ts := newTestServer()
+	defer ts.Close()
+
+	c := client.NewClient(client.NewHTTPTransport(ts.URL), client.Options{})
+	arith := testdata.NewArithServiceClient(c, "arith")
+
+	if res, err := arith.Multiply(context.Background(), 4, 2); err != nil {
+		t.Fatal(err)
+	} else if res != 8 {
+		t.Errorf("got %d expected 8", res)
+	}
+
+	if res, err := arith.Pow(context.Background(), 3, nil); err != nil {
+		t.Fatal(err)
+	} else if res != 9 {
+		t.Errorf("got %v expected 9", res)
+	}
+
+	if quo, err := arith.Divide(context.Background(), 7, 2); err != nil {
+		t.Fatal(err)
+	} else if quo == nil || quo.Quo != 3 || quo.Rem != 1 {
+		t.Errorf("got %+v expected {Quo:3 Rem:1}", quo)
+	}
+
+	if err := arith.CheckZenRPCError(context.Background(), true); err == nil || err.(*zenrpc.Error).Code != 500 {
+		t.Errorf("got %v expected 500 error", err)
+	}
+
+	pb := testdata.NewPhoneBookClient(c, "phonebook")
+	id, err := pb.Save(context.Background(), testdata.Person{FirstName: "John", LastName: "Doe"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if p, err := pb.ById(context.Background(), id); err != nil {
+		t.Fatal(err)
+	} else if p.FirstName != "John" {
+		t.Errorf("got %+v expected John", p)
+	}
+
+	if _, err := pb.ById(context.Background(), 100); err == nil || err.(*zenrpc.Error).Code != 404 {
+		t.Errorf("got %v expected 404 error", err)
+	}
+
+	catalogue := testdata.NewCatalogueServiceClient(c, "catalogue")
+	campaigns := []testdata.Campaign{{Groups: make([]testdata.Group, 3)}, {Groups: make([]testdata.Group, 2)}}
+	if groups, avg, err := catalogue.Stats(context.Background(), campaigns); err != nil {
+		t.Fatal(err)
+	} else if groups != 5 || avg != 2.5 {
+		t.Errorf("got groups=%d avg=%v expected 5 and 2.5", groups, avg)
+	}
+
+	var contacts testdata.Contacts = testdata.NewContactsClient(c, "contacts")
+	if persons, err := contacts.Find(context.Background(), "Jo"); err != nil {
+		t.Fatal(err)
+	} else if len(persons) != 1 || persons[0].ID != 1 {
+		t.Errorf("got persons %+v", persons)
+	}
+}
+
+func TestClient_Notify(t *testing.T) {
+	ts := newTestServer()
+	defer ts.Close()
+
+	c := client.NewClient(client.NewHTTPTransport(ts.URL), client.Options{})
+	if err := c.Notify(context.Background(), "arith.multiply", []int{1, 2}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBatch_Send(t *testing.T) {
+	ts := newTestServer()
+	defer ts.Close()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	rpc := zenrpc.NewServer(zenrpc.Options{})
+	rpc.Register("arith", &testdata.ArithService{})
+	go rpc.ServeTCP(l)
+
+	tcp, err := client.DialTCP(context.Background(), l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tcp.Close()
+
+	for _, tr := range []client.Transport{client.NewHTTPTransport(ts.URL), tcp} {
+		c := client.NewClient(tr, client.Options{})
+
+		// only notifications
+		b := c.NewBatch()
+		b.Notify("arith.multiply", []int{1, 2})
+		b.Notify("arith.pi", nil)
+		if err := b.Send(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+
+		var mul int
+		var pi float64
+		b = c.NewBatch()
+		b.Notify("arith.multiply", []int{1, 2})
+		mulCall := b.Call("arith.multiply", []int{2, 5}, &mul)
+		b.Call("arith.pi", nil, &pi)
+		errCall := b.Call("arith.checkerror", []bool{true}, nil)
+
+		if err := b.Send(context.Background()); err == nil || err.Error() != "test" {
+			t.Errorf("got %v expected test error", err)
+		}
+
+		if mul != 10 || pi != math.Pi || mulCall.Error != nil || errCall.Error == nil || errCall.Error.Code != zenrpc.InternalError {
+			t.Errorf("got mul=%d pi=%v mulErr=%v err=%v", mul, pi, mulCall.Error, errCall.Error)
+		}
+	}
+}
+
+func TestTCPTransport_Reconnect(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	// server echoes lines, slow lines are answered too late
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+
+			go func() {
+				defer conn.Close()
+				r := bufio.NewReader(conn)
+				for {
+					line, err := r.ReadBytes('\n')
+					if err != nil {
+						return
+					}
+
+					if bytes.HasPrefix(line, []byte("slow")) {
+						time.Sleep(100 * time.Millisecond)
+					}
+
+					if _, err := conn.Write(line); err != nil {
+						return
+					}
+				}
+			}()
+		}
+	}()
+
+	tcp, err := client.DialTCP(context.Background(), l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tcp.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := tcp.Send(ctx, []byte("slow")); err == nil {
+		t.Fatal("expected timeout error")
+	}
+
+	// cancellation without deadline interrupts blocked read
+	ctx, cancel = context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	if _, err := tcp.Send(ctx, []byte("slow")); !errors.Is(err, context.Canceled) {
+		t.Fatalf("got error %v expected context canceled", err)
+	}
+
+	// response to slow message must not be read as response to next one
+	time.Sleep(150 * time.Millisecond)
+	if resp, err := tcp.Send(context.Background(), []byte("fast")); err != nil {
+		t.Fatal(err)
+	} else if string(resp) != "fast" {
+		t.Errorf("got response %s expected fast", resp)
+	}
+}
+
+// flakyTransport fails every odd request.
+type flakyTransport struct {
+	client.Transport
+	sent int
+}
+
+func (t *flakyTransport) Send(ctx context.Context, message []byte) ([]byte, error) {
+	t.sent++
+	if t.sent%2 == 1 {
+		return nil, errors.New("connection reset")
+	}
+
+	return t.Transport.Send(ctx, message)
+}
+
+func TestClient_Retries(t *testing.T) {
+	ts := newTestServer()
+	defer ts.Close()
+
+	tr := &flakyTransport{Transport: client.NewHTTPTransport(ts.URL)}
+	c := client.NewClient(tr, client.Options{
+		Retries:           1,
+		Backoff:           func(int) time.Duration { return 0 },
+		IdempotentMethods: []string{"arith.Multiply"},
+	})
+
+	var res int
+	if err := c.Call(context.Background(), "arith.multiply", []int{2, 2}, &res); err != nil {
+		t.Fatal(err)
+	} else if res != 4 || tr.sent != 2 {
+		t.Errorf("got res=%d sent=%d expected res=4 sent=2", res, tr.sent)
+	}
+
+	// not idempotent method
+	tr.sent = 0
+	if err := c.Call(context.Background(), "arith.pi", nil, nil); err == nil || tr.sent != 1 {
+		t.Errorf("got err=%v sent=%d expected transport error", err, tr.sent)
+	}
+}
+
+func TestBalancedTransport(t *testing.T) {
+	ts1, ts2 := newTestServer(), newTestServer()
+	defer ts1.Close()
+	defer ts2.Close()
+
+	tr1, tr2 := &flakyTransport{Transport: client.NewHTTPTransport(ts1.URL)}, &flakyTransport{Transport: client.NewHTTPTransport(ts2.URL), sent: 1}
+	c := client.NewClient(client.NewBalancedTransport(tr1, tr2), client.Options{
+		Retries:           1,
+		Backoff:           func(int) time.Duration { return 0 },
+		IdempotentMethods: []string{"arith.multiply"},
+	})
+
+	// first attempt fails on first transport, retry succeeds on second
+	var res int
+	if err := c.Call(context.Background(), "arith.multiply", []int{2, 3}, &res); err != nil {
+		t.Fatal(err)
+	} else if res != 6 || tr1.sent != 1 || tr2.sent != 2 {
+		t.Errorf("got res=%d sent=%d,%d", res, tr1.sent, tr2.sent)
+	}
+
+	if got := client.ExponentialBackoff(time.Second, 5*time.Second)(3); got != 5*time.Second {
+		t.Errorf("got backoff %v expected 5s", got)
+	}
+}
+
+func TestWSTransport_Concurrent(t *testing.T) {
+	ts := newTestServer()
+	defer ts.Close()
+
+	ws, err := client.DialWS(context.Background(), "ws"+strings.TrimPrefix(ts.URL, "http")+"/ws", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+
+	c := client.NewClient(ws, client.Options{})
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			var res int
+			if err := c.Call(context.Background(), "arith.multiply", []int{i, 2}, &res); err != nil {
+				t.Error(err)
+			} else if res != i*2 {
+				t.Errorf("got %d expected %d", res, i*2)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	// notifications and batches over same connection
+	if err := c.Notify(context.Background(), "arith.pi", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	var pi float64
+	b := c.NewBatch()
+	b.Call("arith.pi", nil, &pi)
+	b.Notify("arith.pi", nil)
+	if err := b.Send(context.Background()); err != nil || pi != math.Pi {
+		t.Errorf("got pi=%v err=%v", pi, err)
+	}
+}
+
+func TestWSTransport_RejectedBatch(t *testing.T) {
+	ts := newTestServer()
+	defer ts.Close()
+
+	ws, err := client.DialWS(context.Background(), "ws"+strings.TrimPrefix(ts.URL, "http")+"/ws", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+
+	// batch over BatchMaxLen is rejected with single response with null id
+	for _, tr := range []client.Transport{client.NewHTTPTransport(ts.URL), ws} {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		b := client.NewClient(tr, client.Options{}).NewBatch()
+		for i := 0; i < 5; i++ {
+			b.Call("arith.pi", nil, nil)
+		}
+
+		err := b.Send(ctx)
+		cancel()
+		if e, ok := err.(*zenrpc.Error); !ok || e.Code != zenrpc.InvalidRequest {
+			t.Errorf("got %#v expected invalid request error", err)
+		}
+	}
+}
+
+func TestWSTransport_Bidirectional(t *testing.T) {
+	// server calls client back via peer for callback namespace
+	rpc := zenrpc.NewServer(zenrpc.Options{AllowCORS: true})
+	rpc.Register("callback", &testdata.ArithService{})
+	rpc.Use(func(h zenrpc.InvokeFunc) zenrpc.InvokeFunc {
+		return func(ctx context.Context, method string, params json.RawMessage) zenrpc.Response {
+			peer, ok := zenrpc.PeerFromContext(ctx)
+			if !ok {
+				return zenrpc.NewResponseError(nil, zenrpc.InternalError, "", "peer not found")
+			}
+
+			resp := zenrpc.Response{}
+			arith := testdata.NewArithServiceClient(client.NewClient(peer, client.Options{}), "client")
+			resp.Set(arith.Multiply(ctx, 7, 6))
+
+			return resp
+		}
+	})
+
+	ts := httptest.NewServer(http.HandlerFunc(rpc.ServeWS))
+	defer ts.Close()
+
+	// client exposes arith service to server
+	handler := zenrpc.NewServer(zenrpc.Options{})
+	handler.Register("client", &testdata.ArithService{})
+
+	ws, err := client.DialWS(context.Background(), "ws"+strings.TrimPrefix(ts.URL, "http"), nil, handler)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+
+	var res int
+	if err := client.NewClient(ws, client.Options{}).Call(context.Background(), "callback.multiply", nil, &res); err != nil {
+		t.Fatal(err)
+	} else if res != 42 {
+		t.Errorf("got %d expected 42", res)
+	}
+}
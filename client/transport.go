@@ -0,0 +1,224 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/semrush/zenrpc/v2"
+)
+
+// contentTypeJSON is content type for HTTP transport.
+const contentTypeJSON = "application/json"
+
+// HTTPTransport sends JSON-RPC 2.0 messages via HTTP POST requests.
+type HTTPTransport struct {
+	// URL is RPC endpoint.
+	URL string
+
+	// Client is HTTP client for requests. If nil, http.DefaultClient will be used.
+	Client *http.Client
+
+	// Header is additional headers for each request.
+	Header http.Header
+}
+
+// NewHTTPTransport returns new HTTP transport for given RPC endpoint.
+func NewHTTPTransport(url string) *HTTPTransport {
+	return &HTTPTransport{URL: url}
+}
+
+// Send sends message via HTTP POST request and returns response body.
+func (t *HTTPTransport) Send(ctx context.Context, message []byte) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodPost, t.URL, bytes.NewReader(message))
+	if err != nil {
+		return nil, err
+	}
+
+	for k, v := range t.Header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", contentTypeJSON)
+
+	client := t.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected http status %s", resp.Status)
+	}
+
+	return io.ReadAll(resp.Body)
+}
+
+// WSTransport sends JSON-RPC 2.0 messages via Gorilla WebSocket connection.
+// Requests are multiplexed over single connection and could be sent concurrently.
+type WSTransport struct {
+	peer *zenrpc.Peer
+}
+
+// DialWS connects to WebSocket RPC endpoint and returns new WebSocket transport.
+// Handler processes requests and notifications sent by server, e.g. zenrpc.Server with registered services.
+// If handler is nil, requests from server are ignored.
+func DialWS(ctx context.Context, url string, header http.Header, handler zenrpc.Handler) (*WSTransport, error) {
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, url, header)
+	if err != nil {
+		return nil, err
+	}
+
+	peer := zenrpc.NewPeer(conn, handler)
+	go peer.Serve(context.Background())
+
+	return &WSTransport{peer: peer}, nil
+}
+
+// Send writes message to connection and waits for response with same ID.
+func (t *WSTransport) Send(ctx context.Context, message []byte) ([]byte, error) {
+	return t.peer.Send(ctx, message)
+}
+
+// Close closes WebSocket connection.
+func (t *WSTransport) Close() error {
+	return t.peer.Close()
+}
+
+// TCPTransport sends JSON-RPC 2.0 messages via TCP connection, one message per line.
+// Server must respond with single line for each message, empty line for notifications. See zenrpc.Server.ServeTCP.
+type TCPTransport struct {
+	addr   string
+	conn   net.Conn // nil after I/O error, connection is dialed again on next message
+	reader *bufio.Reader
+	closed bool
+	mu     sync.Mutex
+}
+
+// DialTCP connects to TCP RPC endpoint and returns new TCP transport.
+func DialTCP(ctx context.Context, addr string) (*TCPTransport, error) {
+	t := &TCPTransport{addr: addr}
+	if err := t.dial(ctx); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+func (t *TCPTransport) dial(ctx context.Context) error {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", t.addr)
+	if err != nil {
+		return err
+	}
+
+	t.conn, t.reader = conn, bufio.NewReader(conn)
+	return nil
+}
+
+// Send writes message line to connection and reads response line. Messages are sent one by one.
+// Connection is closed on any I/O error, because response to failed message could be read by the next one,
+// next message is sent via new connection.
+func (t *TCPTransport) Send(ctx context.Context, message []byte) ([]byte, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.closed {
+		return nil, errors.New("transport is closed")
+	}
+
+	if t.conn == nil {
+		if err := t.dial(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	data, err := t.send(ctx, message)
+	if err != nil {
+		t.conn.Close()
+		t.conn, t.reader = nil, nil
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (t *TCPTransport) send(ctx context.Context, message []byte) ([]byte, error) {
+	deadline, _ := ctx.Deadline()
+	if err := t.conn.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+
+	// cancellation interrupts blocked write or read by deadline in the past
+	conn := t.conn
+	stop := context.AfterFunc(ctx, func() { conn.SetDeadline(time.Unix(1, 0)) })
+	defer stop()
+
+	if _, err := t.conn.Write(append(message, '\n')); err != nil {
+		return nil, contextError(ctx, err)
+	}
+
+	data, err := t.reader.ReadBytes('\n')
+	if err != nil {
+		return nil, contextError(ctx, err)
+	}
+
+	return bytes.TrimSpace(data), nil
+}
+
+// contextError returns context error instead of I/O error caused by cancellation.
+func contextError(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	return err
+}
+
+// Close closes TCP connection.
+func (t *TCPTransport) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.closed = true
+	if t.conn == nil {
+		return nil
+	}
+
+	return t.conn.Close()
+}
+
+// BalancedTransport distributes messages between several transports using round-robin.
+// Combined with Options.Retries it retries idempotent methods on next endpoint.
+type BalancedTransport struct {
+	transports []Transport
+	next       uint64
+}
+
+// NewBalancedTransport returns new round-robin transport for given transports.
+func NewBalancedTransport(transports ...Transport) *BalancedTransport {
+	return &BalancedTransport{transports: transports}
+}
+
+// Send sends message via next transport.
+func (t *BalancedTransport) Send(ctx context.Context, message []byte) ([]byte, error) {
+	if len(t.transports) == 0 {
+		return nil, errors.New("no transports for balancing")
+	}
+
+	i := atomic.AddUint64(&t.next, 1) - 1
+	return t.transports[i%uint64(len(t.transports))].Send(ctx, message)
+}
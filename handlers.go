@@ -1,15 +1,20 @@
 package zenrpc
 
 import (
+	"bufio"
+	"bytes"
+	"context"
 	"encoding/json"
+	"io"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"strings"
-	"time"
-
-	"github.com/gorilla/websocket"
 )
 
+// smdFormatOpenRPC is value of smd GET parameter for OpenRPC document.
+const smdFormatOpenRPC = "openrpc"
+
 type Printer interface {
 	Printf(string, ...interface{})
 }
@@ -23,8 +28,14 @@ func (s Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// check for smd parameter and server settings and write schema if all conditions met,
-	if _, ok := r.URL.Query()["smd"]; ok && s.options.ExposeSMD && r.Method == http.MethodGet {
-		b, _ := json.Marshal(s.SMD())
+	if format, ok := r.URL.Query()["smd"]; ok && s.options.ExposeSMD && r.Method == http.MethodGet {
+		var b []byte
+		if len(format) > 0 && format[0] == smdFormatOpenRPC {
+			b, _ = json.Marshal(s.OpenRPC())
+		} else {
+			b, _ = json.Marshal(s.SMD())
+		}
+
 		w.Write(b)
 		return
 	}
@@ -61,7 +72,7 @@ func (s Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		s.printf("read request body failed with err=%v", err)
 		data = NewResponseError(nil, ParseError, "", nil)
 	} else {
-		data = s.process(newRequestContext(r.Context(), r), b)
+		data = s.process(newTransportContext(newRequestContext(r.Context(), r), TransportHTTP), b)
 	}
 
 	// if responses is empty -> all requests are notifications -> exit immediately
@@ -85,6 +96,8 @@ func (s Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 }
 
 // ServeWS processes JSON-RPC 2.0 requests via Gorilla WebSocket.
+// Requests are processed concurrently, responses are sent as soon as they are ready.
+// Methods could call client back via Peer from context, see PeerFromContext.
 // https://github.com/gorilla/websocket/blob/master/examples/echo/
 func (s Server) ServeWS(w http.ResponseWriter, r *http.Request) {
 	c, err := s.options.Upgrader.Upgrade(w, r, nil)
@@ -94,29 +107,54 @@ func (s Server) ServeWS(w http.ResponseWriter, r *http.Request) {
 	}
 	defer c.Close()
 
-	for {
-		mt, message, err := c.ReadMessage()
+	if err := NewPeer(c, s).Serve(newTransportContext(newRequestContext(r.Context(), r), TransportWS)); err != nil {
+		s.printf("%v", err)
+	}
+}
 
-		// normal closure
-		if websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
-			break
-		}
-		// abnormal closure
+// ServeTCP accepts connections on listener and processes JSON-RPC 2.0 requests from each connection in separate goroutine.
+func (s Server) ServeTCP(l net.Listener) error {
+	for {
+		conn, err := l.Accept()
 		if err != nil {
-			s.printf("read message failed with err=%v", err)
-			break
+			return err
 		}
 
-		data, err := s.Do(newRequestContext(r.Context(), r), message)
+		go s.ServeConn(conn)
+	}
+}
+
+// ServeConn processes newline delimited JSON-RPC 2.0 messages from connection.
+// Each message gets single line response, empty line if all requests are notifications.
+func (s Server) ServeConn(conn net.Conn) {
+	defer conn.Close()
+
+	ctx := newTransportContext(context.Background(), TransportTCP)
+	reader := bufio.NewReader(conn)
+	for {
+		message, err := reader.ReadBytes('\n')
 		if err != nil {
-			s.printf("marshal json response failed with err=%v", err)
-			c.WriteControl(websocket.CloseInternalServerErr, nil, time.Time{})
+			if err != io.EOF {
+				s.printf("read message failed with err=%v", err)
+			}
 			break
 		}
 
-		if err = c.WriteMessage(mt, data); err != nil {
+		// skip empty lines
+		if message = bytes.TrimSpace(message); len(message) == 0 {
+			continue
+		}
+
+		var resp []byte
+		if data := s.process(ctx, message); data != nil {
+			if resp, err = json.Marshal(data); err != nil {
+				s.printf("marshal json response failed with err=%v", err)
+				break
+			}
+		}
+
+		if _, err = conn.Write(append(resp, '\n')); err != nil {
 			s.printf("write response failed with err=%v", err)
-			c.WriteControl(websocket.CloseInternalServerErr, nil, time.Time{})
 			break
 		}
 	}
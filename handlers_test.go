@@ -1,16 +1,20 @@
 package zenrpc_test
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"io/ioutil"
 	"log"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/gorilla/websocket"
 	"github.com/semrush/zenrpc/v2"
@@ -416,6 +420,12 @@ func TestServer_ServeWS(t *testing.T) {
 		{
 			in:  `{"jsonrpc": "2.0", "method": "arith.pow", "params": { "base": 3 }, "id": 0 }`,
 			out: `{"jsonrpc":"2.0","id":0,"result":9}`},
+		{
+			in:  `{bad json`,
+			out: `{"jsonrpc":"2.0","id":null,"error":{"code":-32700,"message":"Parse error"}}`},
+		{
+			in:  `{"jsonrpc":"2.0","id":7}`,
+			out: `{"jsonrpc":"2.0","id":7,"error":{"code":-32600,"message":"Invalid Request"}}`},
 	}
 
 	for _, c := range tc {
@@ -424,6 +434,7 @@ func TestServer_ServeWS(t *testing.T) {
 			return
 		}
 
+		ws.SetReadDeadline(time.Now().Add(time.Second))
 		_, resp, err := ws.ReadMessage()
 		if err != nil {
 			log.Fatal(err)
@@ -440,3 +451,121 @@ func TestServer_ServeWS(t *testing.T) {
 		return
 	}
 }
+
+// failingHandler fails on every message.
+type failingHandler struct{}
+
+func (failingHandler) Do(context.Context, []byte) ([]byte, error) {
+	return nil, errors.New("broken")
+}
+
+func TestPeer_HandlerFailure(t *testing.T) {
+	served := make(chan error, 1)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+		if err != nil {
+			served <- err
+			return
+		}
+
+		served <- zenrpc.NewPeer(c, failingHandler{}).Serve(context.Background())
+	}))
+	defer ts.Close()
+
+	ws, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(ts.URL, "http"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+
+	if err := ws.WriteMessage(websocket.TextMessage, []byte(`{"jsonrpc":"2.0","method":"arith.pi","id":1}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	ws.SetReadDeadline(time.Now().Add(time.Second))
+	if _, _, err := ws.ReadMessage(); !websocket.IsCloseError(err, websocket.CloseInternalServerErr) {
+		t.Errorf("got %v expected internal server error closure", err)
+	}
+
+	if err := <-served; err == nil || err.Error() != "marshal json response failed with err=broken" {
+		t.Errorf("got Serve error %v", err)
+	}
+}
+
+func TestServer_ServeTCP(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	go rpc.ServeTCP(l)
+
+	conn, err := net.Dial("tcp", l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	var tc = []struct {
+		in, out string
+	}{
+		{
+			in:  `{"jsonrpc": "2.0", "method": "arith.divide", "params": { "a": 1, "b": 24 }, "id": 1 }`,
+			out: `{"jsonrpc":"2.0","id":1,"result":{"Quo":0,"rem":1}}`},
+		{
+			in:  `{"jsonrpc": "2.0", "method": "arith.divide", "params": { "a": 1, "b": 24 } }`,
+			out: ``},
+		{
+			in:  `[{"jsonrpc": "2.0", "method": "arith.multiply", "params": { "a": 3, "b": 2 }, "id": 0 }]`,
+			out: `[{"jsonrpc":"2.0","id":0,"result":6}]`},
+	}
+
+	reader := bufio.NewReader(conn)
+	for _, c := range tc {
+		if _, err := conn.Write([]byte(c.in + "\n")); err != nil {
+			t.Fatal(err)
+		}
+
+		resp, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if resp = strings.TrimSpace(resp); resp != c.out {
+			t.Errorf("Input: %s\n got %s expected %s", c.in, resp, c.out)
+		}
+	}
+}
+
+func TestServer_ServeHTTPWithSMD(t *testing.T) {
+	server := zenrpc.NewServer(zenrpc.Options{ExposeSMD: true})
+	server.Register("arith", &testdata.ArithService{})
+
+	ts := httptest.NewServer(http.HandlerFunc(server.ServeHTTP))
+	defer ts.Close()
+
+	var tc = []struct {
+		query, contains string
+	}{
+		{query: "?smd", contains: `"SMDVersion":"2.0"`},
+		{query: "?smd=openrpc", contains: `"openrpc":"1.2.6"`},
+	}
+
+	for _, c := range tc {
+		res, err := http.Get(ts.URL + c.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp, err := ioutil.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !strings.Contains(string(resp), c.contains) {
+			t.Errorf("Query: %s\n got %s expected %s", c.query, resp, c.contains)
+		}
+	}
+}
@@ -0,0 +1,239 @@
+// Package openrpc converts SMD schema to OpenRPC document. See https://spec.open-rpc.org
+package openrpc
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/semrush/zenrpc/v2/smd"
+)
+
+const (
+	// Version is supported OpenRPC specification version.
+	Version = "1.2.6"
+
+	// DiscoverMethod is service discovery method name defined by OpenRPC specification.
+	DiscoverMethod = "rpc.discover"
+
+	smdDefinitionsPrefix = "#/definitions/"
+	schemasPrefix        = "#/components/schemas/"
+)
+
+// Document is OpenRPC document. This struct doesn't implement complete specification.
+type Document struct {
+	// OpenRPC is semantic version number of the OpenRPC Specification version that the document uses.
+	OpenRPC string `json:"openrpc"`
+
+	// Info provides metadata about the API.
+	Info Info `json:"info"`
+
+	// Servers is list of servers with API.
+	Servers []Server `json:"servers,omitempty"`
+
+	// Methods is list of available methods.
+	Methods []Method `json:"methods"`
+
+	// Components holds reusable schemas.
+	Components *Components `json:"components,omitempty"`
+}
+
+// Info provides metadata about the API.
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// Server is a server with API.
+type Server struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// Method describes RPC method.
+type Method struct {
+	Name        string              `json:"name"`
+	Description string              `json:"description,omitempty"`
+	Params      []ContentDescriptor `json:"params"`
+	Result      *ContentDescriptor  `json:"result,omitempty"`
+	Errors      []Error             `json:"errors,omitempty"`
+
+	// ParamStructure is expected params structure: byname, byposition or either.
+	ParamStructure string `json:"paramStructure,omitempty"`
+}
+
+// ContentDescriptor describes parameter or result.
+type ContentDescriptor struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+	Schema      Schema `json:"schema"`
+}
+
+// Error is application defined error.
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Components holds reusable schemas.
+type Components struct {
+	Schemas map[string]Schema `json:"schemas,omitempty"`
+}
+
+// Schema is JSON Schema object.
+type Schema struct {
+	Ref                  string            `json:"$ref,omitempty"`
+	Type                 string            `json:"type,omitempty"`
+	Description          string            `json:"description,omitempty"`
+	Default              *json.RawMessage  `json:"default,omitempty"`
+	Format               string            `json:"format,omitempty"`
+	ContentEncoding      string            `json:"contentEncoding,omitempty"`
+	Items                *Schema           `json:"items,omitempty"`
+	Properties           map[string]Schema `json:"properties,omitempty"`
+	Required             []string          `json:"required,omitempty"`
+	AdditionalProperties *Schema           `json:"additionalProperties,omitempty"`
+	OneOf                []Schema          `json:"oneOf,omitempty"`
+	smd.Constraints
+}
+
+// FromSMD converts SMD schema to OpenRPC document.
+func FromSMD(schema smd.Schema, info Info) Document {
+	doc := Document{
+		OpenRPC: Version,
+		Info:    info,
+		Methods: []Method{},
+	}
+
+	if schema.Target != "" {
+		doc.Servers = []Server{{Name: "default", URL: schema.Target}}
+	}
+
+	for _, name := range sortedServices(schema.Services) {
+		service := schema.Services[name]
+		m := Method{
+			Name:           name,
+			Description:    service.Description,
+			Params:         []ContentDescriptor{},
+			ParamStructure: "either",
+		}
+
+		for _, p := range service.Parameters {
+			m.Params = append(m.Params, ContentDescriptor{
+				Name:        p.Name,
+				Description: p.Description,
+				Required:    !p.Optional,
+				Schema:      newSchema(p),
+			})
+		}
+
+		m.Result = &ContentDescriptor{
+			Name:        "result",
+			Description: service.Returns.Description,
+			Required:    !service.Returns.Optional,
+			Schema:      nullable(newSchema(service.Returns), service.Returns.Optional),
+		}
+
+		for _, code := range sortedErrors(service.Errors) {
+			m.Errors = append(m.Errors, Error{Code: code, Message: service.Errors[code]})
+		}
+
+		doc.Methods = append(doc.Methods, m)
+	}
+
+	if len(schema.Definitions) > 0 {
+		doc.Components = &Components{Schemas: make(map[string]Schema, len(schema.Definitions))}
+		for name, d := range schema.Definitions {
+			doc.Components.Schemas[name] = newPropertySchema(d)
+		}
+	}
+
+	return doc
+}
+
+// newSchema converts SMD parameter or return value to JSON Schema.
+func newSchema(s smd.JSONSchema) Schema {
+	result := newPropertySchema(s.Property())
+	result.Default = s.Default
+
+	return result
+}
+
+// newPropertySchema converts SMD property to JSON Schema.
+func newPropertySchema(p smd.Property) Schema {
+	if p.Ref != "" {
+		return nullable(Schema{Ref: ref(p.Ref), Description: p.Description}, p.Nullable)
+	}
+
+	result := Schema{
+		Type:                 p.Type,
+		Description:          p.Description,
+		Format:               p.Format,
+		ContentEncoding:      p.ContentEncoding,
+		Required:             p.Required,
+		Items:                newSchemaPtr(p.Items),
+		AdditionalProperties: newSchemaPtr(p.AdditionalProperties),
+		Constraints:          p.Constraints,
+	}
+
+	for _, p := range p.OneOf {
+		result.OneOf = append(result.OneOf, newPropertySchema(p))
+	}
+
+	if len(p.Properties) > 0 {
+		result.Properties = make(map[string]Schema, len(p.Properties))
+		for name, p := range p.Properties {
+			result.Properties[name] = newPropertySchema(p)
+		}
+	}
+
+	return nullable(result, p.Nullable)
+}
+
+// nullable wraps schema in oneOf with null type if value could be null, JSON Schema has no nullable keyword.
+func nullable(s Schema, ok bool) Schema {
+	if !ok || s.Type == smd.Any && s.Ref == "" && len(s.OneOf) == 0 {
+		return s
+	}
+
+	description := s.Description
+	s.Description = ""
+
+	return Schema{Description: description, OneOf: []Schema{s, {Type: smd.Null}}}
+}
+
+// newSchemaPtr converts optional SMD property to JSON Schema.
+func newSchemaPtr(p *smd.Property) *Schema {
+	if p == nil {
+		return nil
+	}
+
+	s := newPropertySchema(*p)
+	return &s
+}
+
+// ref converts SMD definition reference to components reference.
+func ref(r string) string {
+	return schemasPrefix + strings.TrimPrefix(r, smdDefinitionsPrefix)
+}
+
+func sortedServices(m map[string]smd.Service) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+func sortedErrors(m map[int]string) []int {
+	keys := make([]int, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+
+	return keys
+}
@@ -0,0 +1,58 @@
+package openrpc
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/semrush/zenrpc/v2/smd"
+)
+
+func TestFromSMD(t *testing.T) {
+	schema := smd.Schema{
+		Target: "/rpc",
+		Services: map[string]smd.Service{
+			"arith.Pow": {
+				Description: "Pow returns x**y.",
+				Parameters: []smd.JSONSchema{
+					{Name: "base", Type: smd.Float},
+					{Name: "exp", Type: smd.Float, Optional: true, Default: smd.RawMessageString("2")},
+				},
+				Returns: smd.JSONSchema{Type: smd.Float},
+			},
+			"arith.GetPoints": {
+				Parameters: []smd.JSONSchema{},
+				Returns: smd.JSONSchema{
+					Type:  smd.Array,
+					Items: &smd.Property{Type: smd.Object, Ref: "#/definitions/Point"},
+				},
+				Errors: map[int]string{500: "internal"},
+			},
+		},
+		Definitions: map[string]smd.Definition{
+			"Point": {
+				Type: smd.Object,
+				Properties: map[string]smd.Property{
+					"X":    {Type: smd.Integer, Description: "coordinate"},
+					"Next": {Type: smd.Object, Ref: "#/definitions/Point", Description: "next point", Nullable: true},
+					"Name": {Type: smd.String, Nullable: true},
+					"Tags": {Type: smd.Object, AdditionalProperties: &smd.Property{Type: smd.String}},
+				},
+				Required: []string{"X"},
+			},
+		},
+	}
+
+	doc := FromSMD(schema, Info{Title: "test", Version: "1.0.0"})
+	b, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"openrpc":"1.2.6","info":{"title":"test","version":"1.0.0"},"servers":[{"name":"default","url":"/rpc"}],"methods":[` +
+		`{"name":"arith.GetPoints","params":[],"result":{"name":"result","required":true,"schema":{"type":"array","items":{"$ref":"#/components/schemas/Point"}}},"errors":[{"code":500,"message":"internal"}],"paramStructure":"either"},` +
+		`{"name":"arith.Pow","description":"Pow returns x**y.","params":[{"name":"base","required":true,"schema":{"type":"number"}},{"name":"exp","schema":{"type":"number","default":2}}],"result":{"name":"result","required":true,"schema":{"type":"number"}},"paramStructure":"either"}],` +
+		`"components":{"schemas":{"Point":{"type":"object","properties":{"Name":{"oneOf":[{"type":"string"},{"type":"null"}]},"Next":{"description":"next point","oneOf":[{"$ref":"#/components/schemas/Point"},{"type":"null"}]},"Tags":{"type":"object","additionalProperties":{"type":"string"}},"X":{"type":"integer","description":"coordinate"}},"required":["X"]}}}}`
+	if string(b) != expected {
+		t.Errorf("got %s\nexpected %s", b, expected)
+	}
+}
@@ -0,0 +1,189 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var constraintRegexp = regexp.MustCompile("^(min|max|minLength|maxLength|minItems|maxItems|pattern|enum)=(`([^`]*)`|([^ ]+))\\s*")
+
+// Constraints are value constraints from magic comments or zenrpc struct tags, e.g. min=1 max=100 pattern=`^\w+$` enum=a,b.
+type Constraints struct {
+	Minimum   string // number as is
+	Maximum   string // number as is
+	MinLength int
+	MaxLength int
+	MinItems  int
+	MaxItems  int
+	Pattern   string
+	Enum      []string // values as is, strings without quotes
+}
+
+// IsEmpty checks that no constraints are set.
+func (c Constraints) IsEmpty() bool {
+	return c.Minimum == "" && c.Maximum == "" && c.MinLength == 0 && c.MaxLength == 0 &&
+		c.MinItems == 0 && c.MaxItems == 0 && c.Pattern == "" && len(c.Enum) == 0
+}
+
+// parseConstraints parses leading constraints from line and returns the rest of line.
+func parseConstraints(line string) (c Constraints, rest string, err error) {
+	rest = strings.TrimSpace(line)
+	for {
+		matches := constraintRegexp.FindStringSubmatch(rest)
+		if matches == nil {
+			return c, rest, nil
+		}
+		rest = rest[len(matches[0]):]
+
+		// quoted value index = 3 can override non quoted value
+		key, value := matches[1], matches[4]
+		if matches[3] != "" {
+			value = matches[3]
+		}
+
+		switch key {
+		case "min", "max":
+			if !isNumber(value) {
+				return c, rest, fmt.Errorf("invalid %s constraint %q: number expected", key, value)
+			}
+
+			if key == "min" {
+				c.Minimum = value
+			} else {
+				c.Maximum = value
+			}
+		case "minLength", "maxLength", "minItems", "maxItems":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				return c, rest, fmt.Errorf("invalid %s constraint %q: non-negative integer expected", key, value)
+			}
+
+			switch key {
+			case "minLength":
+				c.MinLength = n
+			case "maxLength":
+				c.MaxLength = n
+			case "minItems":
+				c.MinItems = n
+			case "maxItems":
+				c.MaxItems = n
+			}
+		case "pattern":
+			if _, err := regexp.Compile(value); err != nil {
+				return c, rest, fmt.Errorf("invalid pattern constraint %q: %s", value, err)
+			}
+			c.Pattern = value
+		case "enum":
+			c.Enum = strings.Split(value, ",")
+		}
+	}
+}
+
+// EnumLiterals returns enum constraint or enum type values as Go literals for SMD generation.
+func (t SMDType) EnumLiterals() []string {
+	values := t.Constraints.Enum
+	if len(values) == 0 {
+		values = t.Enum
+	}
+
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		if t.Type == "String" {
+			v = strconv.Quote(v)
+		}
+		result = append(result, v)
+	}
+
+	return result
+}
+
+// quoted returns type of value encoded in JSON string by string option of json tag.
+// Enum values are converted to strings as encoding/json writes them, e.g. 1e6 is "1000000" and "new" is "\"new\"".
+func (t SMDType) quoted() SMDType {
+	var encode func(string) string
+	switch t.Type {
+	case "Integer", "Boolean":
+		// values are written as is
+	case "Float":
+		encode = func(v string) string {
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return v
+			}
+			return marshalValue(f)
+		}
+	case "String":
+		encode = func(v string) string { return marshalValue(v) }
+	default:
+		return t
+	}
+
+	t.Type = "String"
+	if encode != nil {
+		t.Enum = mapValues(t.Enum, encode)
+		t.Constraints.Enum = mapValues(t.Constraints.Enum, encode)
+	}
+
+	return t
+}
+
+// marshalValue returns JSON encoding of v.
+func marshalValue(v interface{}) string {
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+// mapValues returns values converted by f, nil for empty values.
+func mapValues(values []string, f func(string) string) []string {
+	var result []string
+	for _, v := range values {
+		result = append(result, f(v))
+	}
+
+	return result
+}
+
+// validateEnum checks that enum values match type.
+func (t SMDType) validateEnum() error {
+	for _, v := range t.Constraints.Enum {
+		switch t.Type {
+		case "String":
+		case "Integer":
+			if !isInteger(v) {
+				return fmt.Errorf("invalid enum constraint value %q: integer expected", v)
+			}
+		case "Float":
+			if !isNumber(v) {
+				return fmt.Errorf("invalid enum constraint value %q: number expected", v)
+			}
+		case "Boolean":
+			if _, err := strconv.ParseBool(v); err != nil {
+				return fmt.Errorf("invalid enum constraint value %q: boolean expected", v)
+			}
+		default:
+			return fmt.Errorf("enum constraint is not supported for %s type", strings.ToLower(t.Type))
+		}
+	}
+
+	return nil
+}
+
+// isNumber checks that value is finite number, Inf and NaN are accepted by strconv.ParseFloat but are not Go literals.
+func isNumber(value string) bool {
+	f, err := strconv.ParseFloat(value, 64)
+	return err == nil && !math.IsInf(f, 0) && !math.IsNaN(f)
+}
+
+// isInteger checks that value is signed or unsigned 64-bit integer.
+func isInteger(value string) bool {
+	if _, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return true
+	}
+
+	_, err := strconv.ParseUint(value, 10, 64)
+	return err == nil
+}
@@ -0,0 +1,108 @@
+package parser
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const zenrpcEnumComment = zenrpcMagicPrefix + "enum"
+
+// enumValues returns values of exported constants grouped by their named type declared in pkg, in order of declaration.
+func enumValues(pkg *types.Package) map[*types.TypeName][]string {
+	consts := map[*types.TypeName][]*types.Const{}
+	scope := pkg.Scope()
+	for _, name := range scope.Names() {
+		c, ok := scope.Lookup(name).(*types.Const)
+		if !ok || !c.Exported() {
+			continue
+		}
+
+		if named, ok := c.Type().(*types.Named); ok && named.Obj().Pkg() == pkg {
+			consts[named.Obj()] = append(consts[named.Obj()], c)
+		}
+	}
+
+	result := make(map[*types.TypeName][]string, len(consts))
+	for tn, cs := range consts {
+		sort.Slice(cs, func(i, j int) bool { return cs[i].Pos() < cs[j].Pos() })
+		for _, c := range cs {
+			result[tn] = append(result[tn], constantValue(c.Val()))
+		}
+	}
+
+	return result
+}
+
+// parseEnumComment returns values from //zenrpc:enum [values] comment.
+func parseEnumComment(doc *ast.CommentGroup) ([]string, bool) {
+	if doc == nil {
+		return nil, false
+	}
+
+	for _, comment := range doc.List {
+		line := strings.TrimSpace(comment.Text)
+		if line != zenrpcEnumComment && !strings.HasPrefix(line, zenrpcEnumComment+" ") {
+			continue
+		}
+
+		values := strings.TrimSpace(strings.TrimPrefix(line, zenrpcEnumComment))
+		if values == "" {
+			return nil, true
+		}
+
+		return strings.Split(values, ","), true
+	}
+
+	return nil, false
+}
+
+// validateEnumComment checks that values from //zenrpc:enum comment match underlying type of tn.
+func validateEnumComment(tn *types.TypeName, values []string) error {
+	b, ok := tn.Type().Underlying().(*types.Basic)
+	if !ok {
+		return fmt.Errorf("enum is not supported for %s type", tn.Type().Underlying())
+	}
+
+	for _, v := range values {
+		var err error
+		switch {
+		case b.Info()&types.IsString != 0:
+		case b.Info()&types.IsUnsigned != 0:
+			_, err = strconv.ParseUint(v, 10, 64)
+		case b.Info()&types.IsInteger != 0:
+			_, err = strconv.ParseInt(v, 10, 64)
+		case b.Info()&types.IsFloat != 0:
+			if !isNumber(v) {
+				err = strconv.ErrSyntax
+			}
+		case b.Info()&types.IsBoolean != 0:
+			_, err = strconv.ParseBool(v)
+		default:
+			return fmt.Errorf("enum is not supported for %s type", b)
+		}
+
+		if err != nil {
+			return fmt.Errorf("invalid enum value %q: %s expected", v, b)
+		}
+	}
+
+	return nil
+}
+
+// constantValue returns constant value as is, strings without quotes.
+func constantValue(v constant.Value) string {
+	switch v.Kind() {
+	case constant.String:
+		return constant.StringVal(v)
+	case constant.Float:
+		f, _ := constant.Float64Val(v)
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	default:
+		return v.ExactString()
+	}
+}
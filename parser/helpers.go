@@ -2,20 +2,12 @@ package parser
 
 import (
 	"fmt"
-	"github.com/thoas/go-funk"
-	"go/ast"
+	"go/types"
 	"golang.org/x/tools/go/packages"
 	"path"
 	"strings"
 )
 
-type PackageFiles struct {
-	PackagePath string
-	PackageName string
-
-	AstFiles []*ast.File
-}
-
 func filterFile(filepath string) bool {
 	if !strings.HasSuffix(filepath, goFileSuffix) ||
 		strings.HasSuffix(filepath, GenerateFileSuffix) || strings.HasSuffix(filepath, testFileSuffix) {
@@ -24,66 +16,48 @@ func filterFile(filepath string) bool {
 	return true
 }
 
-func getDependenciesFilenames(dir string) ([]string, error) {
-	goFiles := []string{}
-	pkgs, err := loadPackage(dir)
+// loadPackageWithTypes loads package of file with syntax and type information for it and all its dependencies.
+func loadPackageWithTypes(filename string, buildTags []string) (*packages.Package, error) {
+	pkgs, err := loadPackageWithSyntax(buildTags, path.Dir(filename))
 	if err != nil {
 		return nil, err
 	}
-	for _, pack := range pkgs {
-		goFiles = append(goFiles, goFilesFromPackage(pack)...)
-		for _, childPack := range pack.Imports {
-			goFiles = append(goFiles, goFilesFromPackage(childPack)...)
-		}
-	}
-	return funk.UniqString(goFiles), nil
-}
 
-func GetDependenciesAstFiles(filename string) ([]PackageFiles, error) {
-	pkgs, err := loadPackageWithSyntax(path.Dir(filename))
-	if err != nil {
-		return nil, err
-	}
-	pfs := []PackageFiles{}
-	done := map[string]bool{}
 	for _, pkg := range pkgs {
-		if _, ok := done[pkg.PkgPath]; ok {
-			continue
+		if pkg.Types == nil || pkg.TypesInfo == nil {
+			return nil, fmt.Errorf("can't load types of package %s", pkg.PkgPath)
 		}
 
-		pfs = append(pfs, PackageFiles{
-			PackagePath: pkg.PkgPath,
-			PackageName: pkg.Name,
-			AstFiles:    pkg.Syntax,
-		})
-
-		done[pkg.PkgPath] = true
-
-		for _, childPack := range pkg.Imports {
-			if _, ok := done[childPack.PkgPath]; ok {
-				continue
-			}
+		return pkg, nil
+	}
 
-			pfs = append(pfs, PackageFiles{
-				PackagePath: childPack.PkgPath,
-				PackageName: childPack.Name,
-				AstFiles:    childPack.Syntax,
-			})
+	return nil, fmt.Errorf("package not found for entry point")
+}
 
-			done[childPack.PkgPath] = true
+// dependencies returns package and all its dependencies by their types.
+func dependencies(pkg *packages.Package) map[*types.Package]*packages.Package {
+	result := make(map[*types.Package]*packages.Package)
+	packages.Visit([]*packages.Package{pkg}, nil, func(p *packages.Package) {
+		if p.Types != nil {
+			result[p.Types] = p
 		}
-	}
-	return pfs, nil
+	})
+
+	return result
 }
 
 func goFilesFromPackage(pkg *packages.Package) []string {
 	files := []string{}
-	files = append(files, pkg.GoFiles...)
-	return funk.FilterString(files, filterFile)
+	for _, file := range pkg.GoFiles {
+		if filterFile(file) {
+			files = append(files, file)
+		}
+	}
+	return files
 }
 
-func EntryPointPackageName(filename string) (string, string, error) {
-	pkgs, err := loadPackage(path.Dir(filename))
+func EntryPointPackageName(filename string, buildTags ...string) (string, string, error) {
+	pkgs, err := loadPackage(path.Dir(filename), buildTags)
 	if err != nil {
 		return "", "", err
 	}
@@ -93,17 +67,31 @@ func EntryPointPackageName(filename string) (string, string, error) {
 	return "", "", fmt.Errorf("package not found for entry point")
 }
 
-func loadPackage(path string) ([]*packages.Package, error) {
+func loadPackage(path string, buildTags []string) ([]*packages.Package, error) {
 	return packages.Load(&packages.Config{
-		Mode: packages.NeedImports | packages.NeedFiles | packages.NeedName,
+		Mode:       packages.NeedImports | packages.NeedFiles | packages.NeedName,
+		BuildFlags: buildFlags(buildTags),
 	}, path)
 }
 
-func loadPackageWithSyntax(path string) ([]*packages.Package, error) {
+func loadPackageWithSyntax(buildTags []string, patterns ...string) ([]*packages.Package, error) {
 	return packages.Load(&packages.Config{
+		BuildFlags: buildFlags(buildTags),
 		Mode: packages.NeedImports |
 			packages.NeedFiles |
 			packages.NeedName |
-			packages.NeedSyntax,
-	}, path)
+			packages.NeedSyntax |
+			packages.NeedTypes |
+			packages.NeedTypesInfo |
+			packages.NeedDeps,
+	}, patterns...)
+}
+
+// buildFlags returns go build flags for build tags.
+func buildFlags(buildTags []string) []string {
+	if len(buildTags) == 0 {
+		return nil
+	}
+
+	return []string{"-tags=" + strings.Join(buildTags, ",")}
 }
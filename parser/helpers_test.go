@@ -7,7 +7,7 @@ import (
 
 func TestLoadPackage(t *testing.T) {
 	Convey("Should load package with syntax and imports", t, func() {
-		_, err := loadPackage("../testdata/subservice/subarithservice.go")
+		_, err := loadPackage("../testdata/subservice/subarithservice.go", nil)
 		So(err, ShouldBeNil)
 	})
 }
@@ -2,18 +2,54 @@ package parser
 
 import (
 	"go/ast"
-	"strings"
+	"go/types"
 )
 
-func importNameOrAliasAndPath(i *ast.ImportSpec) (name, path string) {
-	path = i.Path.Value[1 : len(i.Path.Value)-1] // remove quotes ""
-	if i.Name != nil {
-		name = i.Name.Name
-	} else {
-		name = path[strings.LastIndex(path, "/")+1:]
+// collectImportSpecs collects import specs of root package files by imported package names and dot imported packages.
+func (pi *PackageInfo) collectImportSpecs() {
+	info := pi.pkg.TypesInfo
+	for _, f := range pi.pkg.Syntax {
+		for _, spec := range f.Imports {
+			obj := info.Implicits[spec]
+			if spec.Name != nil {
+				obj = info.Defs[spec.Name]
+			}
+
+			pkgName, ok := obj.(*types.PkgName)
+			if !ok {
+				continue
+			}
+
+			pi.importSpecs[pkgName] = spec
+			if pkgName.Name() == "." {
+				pi.dotImports[pkgName.Imported()] = spec
+			}
+		}
 	}
+}
 
-	return
+// usedImports returns imports of packages used in type expression of root package.
+func (pi *PackageInfo) usedImports(expr ast.Expr) (imports []*ast.ImportSpec) {
+	info := pi.pkg.TypesInfo
+	ast.Inspect(expr, func(n ast.Node) bool {
+		switch v := n.(type) {
+		case *ast.SelectorExpr:
+			if x, ok := v.X.(*ast.Ident); ok {
+				if pkgName, ok := info.Uses[x].(*types.PkgName); ok && pi.importSpecs[pkgName] != nil {
+					imports = append(imports, pi.importSpecs[pkgName])
+				}
+			}
+			return false
+		case *ast.Ident:
+			if tn, ok := info.Uses[v].(*types.TypeName); ok && tn.Pkg() != nil && pi.dotImports[tn.Pkg()] != nil {
+				imports = append(imports, pi.dotImports[tn.Pkg()])
+			}
+		}
+
+		return true
+	})
+
+	return imports
 }
 
 func uniqueImports(in []*ast.ImportSpec) (out []*ast.ImportSpec) {
@@ -32,15 +68,3 @@ func uniqueImports(in []*ast.ImportSpec) (out []*ast.ImportSpec) {
 
 	return
 }
-
-// filterImports filter imports by namespace in structs
-func filterImports(in []*ast.ImportSpec, names map[string]struct{}) (out []*ast.ImportSpec) {
-	for _, i := range in {
-		name, _ := importNameOrAliasAndPath(i)
-		if _, ok := names[name]; ok {
-			out = append(out, i)
-		}
-	}
-
-	return
-}
@@ -0,0 +1,129 @@
+package parser
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+)
+
+const zenrpcTypeComment = zenrpcMagicPrefix + "type"
+
+// smdTypeNames are JSON Schema type names with SMDType.Type values for custom type mappings.
+var smdTypeNames = map[string]string{
+	"string":  "String",
+	"integer": "Integer",
+	"number":  "Float",
+	"boolean": "Boolean",
+	"array":   "Array",
+	"object":  "Object",
+	"any":     "Any",
+}
+
+var (
+	// jsonMarshaler is encoding/json.Marshaler interface, such types are described as any value.
+	jsonMarshaler = newInterface("MarshalJSON", types.NewSlice(types.Typ[types.Byte]))
+
+	// textMarshaler is encoding.TextMarshaler interface, such types are described as string.
+	textMarshaler = newInterface("MarshalText", types.NewSlice(types.Typ[types.Byte]))
+)
+
+// ParseTypeMapping parses custom type mapping <package path>.<type>=<type>[:<format>], e.g.
+// time.Duration=integer or github.com/google/uuid.UUID=string:uuid.
+func ParseTypeMapping(s string) (name string, t SMDType, err error) {
+	i := strings.Index(s, "=")
+	if i == -1 || !strings.Contains(s[:i], ".") {
+		return "", t, fmt.Errorf("invalid type mapping %q: <package path>.<type>=<type>[:<format>] expected", s)
+	}
+
+	t, err = parseMappedType(s[i+1:])
+	if err != nil {
+		return "", t, fmt.Errorf("invalid type mapping %q: %s", s, err)
+	}
+
+	return s[:i], t, nil
+}
+
+// parseMappedType parses <type>[:<format>], e.g. string:uuid.
+func parseMappedType(value string) (SMDType, error) {
+	name, format := strings.TrimSpace(value), ""
+	if i := strings.Index(name, ":"); i != -1 {
+		name, format = name[:i], name[i+1:]
+	}
+
+	t, ok := smdTypeNames[name]
+	if !ok {
+		return SMDType{}, fmt.Errorf("unknown type %q, expected one of string, integer, number, boolean, array, object, any", name)
+	}
+
+	return SMDType{Type: t, Format: format}, nil
+}
+
+// parseTypeComment returns mapped type from //zenrpc:type <type>[:<format>] comment.
+func parseTypeComment(doc *ast.CommentGroup) (SMDType, bool, error) {
+	if doc == nil {
+		return SMDType{}, false, nil
+	}
+
+	for _, comment := range doc.List {
+		line := strings.TrimSpace(comment.Text)
+		if strings.HasPrefix(line, zenrpcTypeComment+" ") {
+			t, err := parseMappedType(strings.TrimPrefix(line, zenrpcTypeComment))
+			return t, true, err
+		}
+	}
+
+	return SMDType{}, false, nil
+}
+
+// mappedType returns SMD type of named type from custom mappings, //zenrpc:type comment, well-known types
+// or types with custom JSON marshaling.
+func (pi *PackageInfo) mappedType(named *types.Named) (SMDType, bool) {
+	tn := named.Obj()
+	if tn.Pkg() == nil {
+		return SMDType{}, false
+	}
+
+	fullName := tn.Pkg().Path() + "." + tn.Name()
+	if t, ok := pi.TypeMappings[fullName]; ok {
+		return t, true
+	}
+
+	pi.index(tn.Pkg())
+	if t, ok := pi.typeComments[tn]; ok {
+		return t, true
+	}
+
+	switch fullName {
+	case "time.Time":
+		return SMDType{Type: "String", Format: "date-time"}, true
+	case "encoding/json.RawMessage":
+		return SMDType{Type: "Any"}, true
+	}
+
+	switch {
+	case implements(named, jsonMarshaler):
+		return SMDType{Type: "Any"}, true
+	case implements(named, textMarshaler):
+		return SMDType{Type: "String"}, true
+	}
+
+	return SMDType{}, false
+}
+
+// implements checks that type or pointer to type implements interface.
+func implements(t types.Type, iface *types.Interface) bool {
+	return types.Implements(t, iface) || types.Implements(types.NewPointer(t), iface)
+}
+
+// newInterface returns interface with single method without arguments which returns result and error.
+func newInterface(method string, result types.Type) *types.Interface {
+	results := types.NewTuple(
+		types.NewVar(token.NoPos, nil, "", result),
+		types.NewVar(token.NoPos, nil, "", types.Universe.Lookup("error").Type()),
+	)
+	sig := types.NewSignatureType(nil, nil, nil, nil, results, false)
+
+	return types.NewInterfaceType([]*types.Func{types.NewFunc(token.NoPos, nil, method, sig)}, nil).Complete()
+}
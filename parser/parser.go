@@ -3,9 +3,13 @@ package parser
 import (
 	"fmt"
 	"go/ast"
+	"go/printer"
 	"go/token"
+	"go/types"
+	"golang.org/x/tools/go/packages"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"unicode"
@@ -13,19 +17,21 @@ import (
 )
 
 const (
-	GenerateFileSuffix = "_zenrpc.go"
+	GenerateFileSuffix       = "_zenrpc.go"
+	GenerateClientFileSuffix = "_client" + GenerateFileSuffix
 
 	zenrpcComment     = "//zenrpc"
-	zenrpcService     = "zenrpc.Service"
-	contextTypeName   = "context.Context"
 	errorTypeName     = "zenrpc.Error"
 	testFileSuffix    = "_test.go"
 	goFileSuffix      = ".go"
 	zenrpcMagicPrefix = "//zenrpc:"
+	zenrpcTag         = "zenrpc"
+	oneOfOption       = "oneOf="
 )
 
 var errorCommentRegexp = regexp.MustCompile("^(-?\\d+)\\s*(.*)$")
 var returnCommentRegexp = regexp.MustCompile("return\\s*(.*)")
+var generatedMethodRegexp = regexp.MustCompile("(missing method|has no field or method) (SMD|Invoke)\\b")
 var argumentCommentRegexp = regexp.MustCompile("([^=( ]+)\\s*(\\(\\s*([^ )]+)\\s*\\))?(\\s*=\\s*((`([^`]+)`)|([^ ]+)))?\\s*(.*)")
 
 // PackageInfo represents struct info for XXX_zenrpc.go file generation
@@ -37,12 +43,32 @@ type PackageInfo struct {
 
 	Services []*Service
 
-	Scopes  map[string][]*ast.Scope // key - import name, value - array of scopes from each package file
 	Structs map[string]*Struct
-	Imports []*ast.ImportSpec
 
-	PackageNamesAndAliasesUsedInServices map[string]struct{} // set of structs names from arguments for printing imports
-	ImportsIncludedToGeneratedCode       []*ast.ImportSpec
+	// TypeMappings are custom SMD types of named types by full name, e.g. time.Duration, see ParseTypeMapping.
+	TypeMappings map[string]SMDType
+
+	// OutputFile overrides name of generated file, it is skipped on parsing as previously generated code.
+	OutputFile string
+	// ServiceNames limits generated services, all services of package are generated if empty.
+	ServiceNames []string
+	// BuildTags are passed to go/packages on loading of package.
+	BuildTags []string
+
+	ImportsIncludedToGeneratedCode   []*ast.ImportSpec
+	ImportsIncludedToGeneratedClient []*ast.ImportSpec
+
+	pkg          *packages.Package                     // root package with types info
+	packages     map[*types.Package]*packages.Package  // root package and all its dependencies
+	importSpecs  map[*types.PkgName]*ast.ImportSpec    // imports of root package files
+	dotImports   map[*types.Package]*ast.ImportSpec    // dot imports of root package files
+	indexed      map[*types.Package]bool               // packages with collected fields, docs and enums
+	fields       map[token.Pos]*ast.Field              // struct fields by position of name
+	typeDocs     map[*types.TypeName]*ast.CommentGroup // doc comments of named types
+	enums        map[*types.TypeName][]string          // enum values of named types
+	typeComments map[*types.TypeName]SMDType           // types from //zenrpc:type comments of named types
+	err          error                                 // first error of magic comments of named types
+	resolving    map[*types.TypeName]bool              // named types in resolution for recursion check
 }
 
 type Service struct {
@@ -50,6 +76,34 @@ type Service struct {
 	Name        string
 	Methods     []*Method
 	Description string
+	Interface   bool // service is declared as interface, generated Invoker adapter wraps its implementations
+
+	iface *ast.InterfaceType
+}
+
+// InvokerName returns name of type which implements zenrpc.Invoker for service.
+func (s Service) InvokerName() string {
+	if s.Interface {
+		return s.Name + "Invoker"
+	}
+
+	return s.Name
+}
+
+// ClientImplements checks that generated client implements interface service:
+// all methods have context as first argument and error as last return value.
+func (s Service) ClientImplements() bool {
+	if !s.Interface || len(s.Methods) != s.iface.Methods.NumFields() {
+		return false
+	}
+
+	for _, m := range s.Methods {
+		if !m.HasContext || len(m.Returns) == 0 || m.Returns[len(m.Returns)-1].Type != "error" {
+			return false
+		}
+	}
+
+	return true
 }
 
 type Method struct {
@@ -61,9 +115,13 @@ type Method struct {
 	DefaultValues map[string]DefaultValue
 	Returns       []Return
 	SMDReturn     *SMDReturn // return for generate smd schema; pointer for nil check
+	ResultStruct  bool       // multiple named return values are packed into result object
 	Description   string
 
 	Errors []SMDError // errors for documentation in SMD
+
+	imports []*ast.ImportSpec // imports used in arguments
+	returns []*ast.ImportSpec // imports used in returns
 }
 
 type DefaultValue struct {
@@ -81,13 +139,20 @@ type Arg struct {
 	JsonName        string
 	HasStar         bool
 	HasDefaultValue bool
+	Variadic        bool   // last argument of variadic method, passed as array
 	Description     string // from magic comment
 	SMDType         SMDType
 }
 
 type Return struct {
-	Name string
-	Type string
+	Name    string
+	Type    string
+	IsError bool // error or *zenrpc.Error
+}
+
+// CapitalName returns name of result object field for return value.
+func (r Return) CapitalName() string {
+	return strings.Title(r.Name)
 }
 
 type SMDReturn struct {
@@ -95,27 +160,39 @@ type SMDReturn struct {
 	HasStar     bool
 	Description string
 	SMDType     SMDType
+
+	typ types.Type
 }
 
 type Struct struct {
 	Name       string // key in map, Ref in arguments and returns
 	Namespace  string
 	Type       string
-	StructType *ast.StructType
 	Properties []Property // array because order is important
+
+	typ *types.Struct
+	pkg *types.Package
 }
 
 type Property struct {
 	Name        string
 	Description string
+	Optional    bool // pointer or omitempty field
+	Nullable    bool // pointer field
 	SMDType     SMDType
 }
 
 // SMDType is a type representation for SMD generation
 type SMDType struct {
-	Type      string
-	ItemsType string // for array
-	Ref       string // for object and also if array item is object
+	Type            string    // Any for any JSON value
+	Format          string    // e.g. date-time
+	ContentEncoding string    // e.g. base64
+	Ref             string    // for object, name of struct from PackageInfo.Structs
+	Items           *SMDType  // for array
+	Values          *SMDType  // for map
+	OneOf           []SMDType // for value of one of types from //zenrpc:return comment
+	Enum            []string  // values of enum type from constants or //zenrpc:enum comment
+	Constraints     Constraints
 }
 
 type SMDError struct {
@@ -123,85 +200,192 @@ type SMDError struct {
 	Description string
 }
 
-func NewPackageInfo(filename string) (*PackageInfo, error) {
+// NewPackageInfo returns package info of package containing filename, build tags are used for loading of package.
+func NewPackageInfo(filename string, buildTags ...string) (*PackageInfo, error) {
 	dir, err := filepath.Abs(filepath.Dir(filename))
 	if err != nil {
 		return nil, err
 	}
 
-	packageName, packagePath, err := EntryPointPackageName(filename)
+	packageName, packagePath, err := EntryPointPackageName(filename, buildTags...)
 	if err != nil {
 		return nil, err
 	}
 
+	pi := newPackageInfo(filename, dir, packageName, packagePath)
+	pi.BuildTags = buildTags
+	return pi, nil
+}
+
+// LoadPackages loads all packages matching patterns, e.g. ./..., with types in single pass.
+// Returned packages are not parsed yet, see ParsePackage.
+func LoadPackages(buildTags []string, patterns ...string) ([]*PackageInfo, error) {
+	pkgs, err := loadPackageWithSyntax(buildTags, patterns...)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*PackageInfo
+	for _, pkg := range pkgs {
+		files := goFilesFromPackage(pkg)
+		if len(files) == 0 {
+			continue // package consists of generated or test files only
+		}
+
+		if pkg.Types == nil || pkg.TypesInfo == nil {
+			return nil, fmt.Errorf("can't load types of package %s", pkg.PkgPath)
+		}
+
+		pi := newPackageInfo(files[0], filepath.Dir(files[0]), pkg.Name, pkg.PkgPath)
+		pi.BuildTags = buildTags
+		pi.pkg = pkg
+		result = append(result, pi)
+	}
+
+	return result, nil
+}
+
+func newPackageInfo(entryPoint, dir, packageName, packagePath string) *PackageInfo {
 	return &PackageInfo{
-		EntryPoint:  filename,
+		EntryPoint:  entryPoint,
 		Dir:         dir,
 		PackageName: packageName,
 		PackagePath: packagePath,
 		Services:    []*Service{},
 
-		Scopes:  make(map[string][]*ast.Scope),
-		Structs: make(map[string]*Struct),
-		Imports: []*ast.ImportSpec{},
+		Structs:      make(map[string]*Struct),
+		TypeMappings: make(map[string]SMDType),
+
+		ImportsIncludedToGeneratedCode:   []*ast.ImportSpec{},
+		ImportsIncludedToGeneratedClient: []*ast.ImportSpec{},
 
-		PackageNamesAndAliasesUsedInServices: make(map[string]struct{}),
-		ImportsIncludedToGeneratedCode:       []*ast.ImportSpec{},
-	}, nil
+		importSpecs:  make(map[*types.PkgName]*ast.ImportSpec),
+		dotImports:   make(map[*types.Package]*ast.ImportSpec),
+		indexed:      make(map[*types.Package]bool),
+		fields:       make(map[token.Pos]*ast.Field),
+		typeDocs:     make(map[*types.TypeName]*ast.CommentGroup),
+		enums:        make(map[*types.TypeName][]string),
+		typeComments: make(map[*types.TypeName]SMDType),
+		resolving:    make(map[*types.TypeName]bool),
+	}
 }
 
-// ParseFiles parse all files associated with package from original file
+// Parse parses services of package from original file, all types are resolved with type checker
 func (pi *PackageInfo) Parse(filename string) error {
-	pfs, err := GetDependenciesAstFiles(filename)
+	pkg, err := loadPackageWithTypes(filename, pi.BuildTags)
 	if err != nil {
 		return err
 	}
 
-	for _, pkg := range pfs {
-		for _, astFile := range pkg.AstFiles {
-			if pkg.PackagePath == pi.PackagePath {
-				// get structs for zenrpc only for root package
-				pi.collectServices(astFile)
-			}
-			// collect scopes
-			pi.collectScopes(astFile)
-			// get imports
-			pi.collectImports(astFile)
+	pi.pkg = pkg
+	return pi.ParsePackage()
+}
+
+// ParsePackage parses services of package loaded by LoadPackages.
+func (pi *PackageInfo) ParsePackage() error {
+	pkg := pi.pkg
+	pi.packages = dependencies(pkg)
+	pi.collectImportSpecs()
+
+	output, err := filepath.Abs(pi.OutputFilename())
+	if err != nil {
+		return err
+	}
+
+	// skip previously generated files
+	var files []*ast.File
+	for _, f := range pkg.Syntax {
+		filename := pkg.Fset.Position(f.Pos()).Filename
+		if !strings.HasSuffix(filename, GenerateFileSuffix) && filename != output {
+			files = append(files, f)
 		}
 	}
 
+	for _, f := range files {
+		pi.collectServices(f)
+	}
+
+	if err := pi.packageErrors(output); err != nil {
+		return err
+	}
+
 	// second loop: parse methods. It runs in separate loop because we need all services to be collected for this parsing
-	for _, pkg := range pfs {
-		for _, f := range pkg.AstFiles {
-			if err := pi.parseMethods(f); err != nil {
-				return err
-			}
+	for _, f := range files {
+		if err := pi.parseMethods(f); err != nil {
+			return err
 		}
 	}
 
-	// collect imports for generated code - only include imports that are explicitly imported in service code (all imports with definitions are more)
-	pi.collectImportsForGeneratedCode()
+	if err := pi.parseInterfaceMethods(); err != nil {
+		return err
+	}
 
-	pi.parseStructs()
+	if err := pi.promoteMethods(); err != nil {
+		return err
+	}
 
-	return nil
-}
+	pi.selectServices()
 
-func (pi *PackageInfo) collectScopes(astFile *ast.File) {
-	if pi.PackageName != astFile.Name.Name {
-		pi.Scopes[astFile.Name.Name] = append(pi.Scopes[astFile.Name.Name], astFile.Scope) // collect other package scopes
-	} else {
-		pi.Scopes["."] = append(pi.Scopes["."], astFile.Scope) // collect current package scopes
+	// collect imports for generated code - only include imports that are explicitly used in service methods
+	var imports, returns []*ast.ImportSpec
+	for _, s := range pi.Services {
+		for _, m := range s.Methods {
+			imports = append(imports, m.imports...)
+			returns = append(returns, m.returns...)
+		}
 	}
-}
 
-func (pi *PackageInfo) collectImports(astFile *ast.File) {
-	pi.Imports = append(pi.Imports, astFile.Imports...) // collect imports
+	pi.ImportsIncludedToGeneratedCode = uniqueImports(imports)
+	// client uses types from both arguments and returns
+	pi.ImportsIncludedToGeneratedClient = uniqueImports(append(imports, returns...))
+
+	if err := pi.parseStructs(); err != nil {
+		return err
+	}
+
+	return pi.err
 }
 
-func (pi *PackageInfo) collectImportsForGeneratedCode() {
-	// collect scopes from imported packages
-	pi.ImportsIncludedToGeneratedCode = filterImports(uniqueImports(pi.Imports), pi.PackageNamesAndAliasesUsedInServices)
+// packageErrors returns type errors of package. Errors in previously generated files
+// and references to declarations of generated code are skipped, generated files are replaced on generation.
+func (pi *PackageInfo) packageErrors(output string) error {
+	generated := map[string]bool{"RPC": true}
+	for _, s := range pi.Services {
+		for _, name := range []string{"smd" + s.Name, s.InvokerName(), s.Name + "Client"} {
+			generated[name], generated["New"+name] = true, true
+		}
+	}
+
+	var errs []string
+	for _, e := range pi.pkg.Errors {
+		// position is file:line:col
+		filename := e.Pos
+		for i := 0; i < 2; i++ {
+			if idx := strings.LastIndex(filename, ":"); idx != -1 {
+				filename = filename[:idx]
+			}
+		}
+
+		if e.Pos != "" && (strings.HasSuffix(filename, GenerateFileSuffix) || filename == output) {
+			continue
+		}
+
+		if name := strings.TrimPrefix(e.Msg, "undefined: "); name != e.Msg && generated[name] {
+			continue
+		}
+
+		if generatedMethodRegexp.MatchString(e.Msg) {
+			continue
+		}
+
+		errs = append(errs, e.Error())
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("package %s has errors:\n%s", pi.PackagePath, strings.Join(errs, "\n"))
 }
 
 func (pi *PackageInfo) collectServices(f *ast.File) {
@@ -221,19 +405,34 @@ func (pi *PackageInfo) collectServices(f *ast.File) {
 				continue
 			}
 
-			structType, ok := spec.Type.(*ast.StructType)
-			if !ok {
-				continue
+			doc := spec.Doc
+			if doc == nil && len(gdecl.Specs) == 1 {
+				doc = gdecl.Doc
 			}
 
-			// check that struct is our zenrpc struct
-			if hasZenrpcComment(spec) || hasZenrpcService(structType) {
-				pi.Services = append(pi.Services, &Service{
-					GenDecl:     gdecl,
-					Name:        spec.Name.Name,
-					Methods:     []*Method{},
-					Description: parseCommentGroup(spec.Doc),
-				})
+			switch t := spec.Type.(type) {
+			case *ast.StructType:
+				// check that struct is our zenrpc struct
+				if hasZenrpcComment(spec) || pi.hasZenrpcService(t) {
+					pi.Services = append(pi.Services, &Service{
+						GenDecl:     gdecl,
+						Name:        spec.Name.Name,
+						Methods:     []*Method{},
+						Description: parseCommentGroup(spec.Doc),
+					})
+				}
+			case *ast.InterfaceType:
+				// interface service is marked by comment
+				if hasZenrpcComment(spec) || hasZenrpcDoc(doc) {
+					pi.Services = append(pi.Services, &Service{
+						GenDecl:     gdecl,
+						Name:        spec.Name.Name,
+						Methods:     []*Method{},
+						Description: parseCommentGroup(doc),
+						Interface:   true,
+						iface:       t,
+					})
+				}
 			}
 		}
 	}
@@ -246,17 +445,7 @@ func (pi *PackageInfo) parseMethods(f *ast.File) error {
 			continue
 		}
 
-		m := Method{
-			FuncDecl:      fdecl.Type,
-			Name:          fdecl.Name.Name,
-			LowerCaseName: strings.ToLower(fdecl.Name.Name),
-			Args:          []Arg{},
-			DefaultValues: make(map[string]DefaultValue),
-			Returns:       []Return{},
-			Description:   parseCommentGroup(fdecl.Doc),
-			Errors:        []SMDError{},
-		}
-
+		m := newMethod(fdecl)
 		serviceNames := m.linkWithServices(pi, fdecl)
 
 		// services not found
@@ -264,16 +453,78 @@ func (pi *PackageInfo) parseMethods(f *ast.File) error {
 			continue
 		}
 
-		if err := m.parseArguments(pi, fdecl, serviceNames); err != nil {
+		if err := m.parse(pi, fdecl, serviceNames); err != nil {
 			return err
 		}
+	}
 
-		if err := m.parseReturns(pi, fdecl, serviceNames); err != nil {
-			return err
+	return nil
+}
+
+// parseInterfaceMethods parses methods declared in interface services.
+func (pi *PackageInfo) parseInterfaceMethods() error {
+	for _, s := range pi.Services {
+		if s.iface == nil {
+			continue
 		}
 
-		// parse default values
-		m.parseComments(fdecl.Doc, pi)
+		for _, field := range s.iface.Methods.List {
+			ftype, ok := field.Type.(*ast.FuncType)
+			if !ok || len(field.Names) == 0 {
+				return fmt.Errorf("%s: embedded interface %s in %s is not supported",
+					pi.pkg.Fset.Position(field.Pos()), pi.exprString(field.Type), s.Name)
+			}
+
+			if !ast.IsExported(field.Names[0].Name) {
+				continue
+			}
+
+			// arguments are passed by names
+			for _, param := range ftype.Params.List {
+				if len(param.Names) == 0 || param.Names[0].Name == "_" {
+					return fmt.Errorf("%s: arguments of %s.%s should be named",
+						pi.pkg.Fset.Position(param.Pos()), s.Name, field.Names[0].Name)
+				}
+			}
+
+			fdecl := &ast.FuncDecl{Doc: field.Doc, Name: field.Names[0], Type: ftype}
+			m := newMethod(fdecl)
+			s.Methods = append(s.Methods, m)
+			if err := m.parse(pi, fdecl, []string{s.Name}); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func newMethod(fdecl *ast.FuncDecl) *Method {
+	return &Method{
+		FuncDecl:      fdecl.Type,
+		Name:          fdecl.Name.Name,
+		LowerCaseName: strings.ToLower(fdecl.Name.Name),
+		Args:          []Arg{},
+		DefaultValues: make(map[string]DefaultValue),
+		Returns:       []Return{},
+		Description:   parseCommentGroup(fdecl.Doc),
+		Errors:        []SMDError{},
+	}
+}
+
+// parse parses method arguments, returns and magic comments.
+func (m *Method) parse(pi *PackageInfo, fdecl *ast.FuncDecl, serviceNames []string) error {
+	if err := m.parseArguments(pi, fdecl, serviceNames); err != nil {
+		return err
+	}
+
+	if err := m.parseReturns(pi, fdecl, serviceNames); err != nil {
+		return err
+	}
+
+	// parse default values
+	if err := m.parseComments(fdecl.Doc, pi); err != nil {
+		return fmt.Errorf("%s in %s", err, strings.Join(serviceNames, ", "))
 	}
 
 	return nil
@@ -330,9 +581,29 @@ func (pi PackageInfo) String() string {
 }
 
 func (pi PackageInfo) OutputFilename() string {
+	if pi.OutputFile != "" {
+		return pi.OutputFile
+	}
+
 	return filepath.Join(pi.Dir, pi.PackageName+GenerateFileSuffix)
 }
 
+// ClientOutputFilename returns file name for generated typed client.
+func (pi PackageInfo) ClientOutputFilename() string {
+	return filepath.Join(pi.Dir, pi.PackageName+GenerateClientFileSuffix)
+}
+
+// ClientReturn returns return value type for generated client or empty string if method returns only error.
+func (m Method) ClientReturn() string {
+	for _, r := range m.Returns {
+		if !r.IsError {
+			return r.Type
+		}
+	}
+
+	return ""
+}
+
 // HasErrorVariable define adding err variable to generated Invoke function
 func (s Service) HasErrorVariable() bool {
 	for _, m := range s.Methods {
@@ -343,34 +614,138 @@ func (s Service) HasErrorVariable() bool {
 	return false
 }
 
-// linkWithServices add method for services
-func (m *Method) linkWithServices(pi *PackageInfo, fdecl *ast.FuncDecl) (names []string) {
-	for _, field := range fdecl.Recv.List {
-		// field can be pointer or not
-		var ident *ast.Ident
-		if starExpr, ok := field.Type.(*ast.StarExpr); ok {
-			if ident, ok = starExpr.X.(*ast.Ident); !ok {
+// promoteMethods adds methods promoted from embedded services to services by Go selector rules:
+// own methods and methods of shallower embedded services shadow deeper ones, ambiguous methods are skipped.
+// Services from other packages can't be promoted, because generated code would need their argument types qualified.
+func (pi *PackageInfo) promoteMethods() error {
+	// own methods of services before promotion
+	declared := make(map[*types.TypeName]*Service, len(pi.Services))
+	own := make(map[*Service][]*Method, len(pi.Services))
+	for _, s := range pi.Services {
+		if tn, ok := pi.pkg.Types.Scope().Lookup(s.Name).(*types.TypeName); ok {
+			declared[tn] = s
+		}
+		own[s] = s.Methods
+	}
+
+	for _, s := range pi.Services {
+		tn, ok := pi.pkg.Types.Scope().Lookup(s.Name).(*types.TypeName)
+		if !ok || s.Interface {
+			continue
+		}
+
+		// promoted method names with declaring service
+		promoted := make(map[string]*Service)
+		mset := types.NewMethodSet(types.NewPointer(tn.Type()))
+		for i := 0; i < mset.Len(); i++ {
+			sel := mset.At(i)
+			if len(sel.Index()) < 2 {
+				continue // own method
+			}
+
+			recv := derefType(sel.Obj().(*types.Func).Type().(*types.Signature).Recv().Type())
+			named, ok := types.Unalias(recv).(*types.Named)
+			if !ok {
 				continue
 			}
-		} else if ident, ok = field.Type.(*ast.Ident); !ok {
-			continue
+
+			if declared[named.Obj()] != nil {
+				promoted[sel.Obj().Name()] = declared[named.Obj()]
+			} else if named.Obj().Pkg() != tn.Pkg() && pi.isService(named) {
+				return fmt.Errorf("service %s embeds service %s from another package, its methods can't be promoted", s.Name, named)
+			}
 		}
 
-		if !ast.IsExported(fdecl.Name.Name) {
-			continue
+		// keep order of services and their methods declaration
+		for _, embedded := range pi.Services {
+			for _, m := range own[embedded] {
+				if promoted[m.Name] == embedded {
+					s.Methods = append(s.Methods, m)
+				}
+			}
 		}
+	}
+
+	return nil
+}
+
+// isService checks that struct type from any loaded package is zenrpc service: marked with //zenrpc comment or embeds zenrpc.Service.
+func (pi *PackageInfo) isService(named *types.Named) bool {
+	st, ok := named.Underlying().(*types.Struct)
+	if !ok {
+		return false
+	}
+
+	for i := 0; i < st.NumFields(); i++ {
+		if st.Field(i).Embedded() && isNamed(st.Field(i).Type(), zenrpcPackagePath, "Service") {
+			return true
+		}
+	}
+
+	p, ok := pi.packages[named.Obj().Pkg()]
+	if !ok {
+		return false
+	}
+
+	for _, f := range p.Syntax {
+		for _, decl := range f.Decls {
+			gdecl, ok := decl.(*ast.GenDecl)
+			if !ok || gdecl.Tok != token.TYPE {
+				continue
+			}
 
-		// find service in our service list
-		// method can be in several services
-		for _, s := range pi.Services {
-			if s.Name == ident.Name {
-				names = append(names, s.Name)
-				s.Methods = append(s.Methods, m)
-				break
+			for _, spec := range gdecl.Specs {
+				if spec, ok := spec.(*ast.TypeSpec); ok && spec.Name.Name == named.Obj().Name() {
+					return hasZenrpcComment(spec)
+				}
 			}
 		}
 	}
 
+	return false
+}
+
+// selectServices keeps only services listed in ServiceNames. It runs after promotion of methods,
+// because selected service could embed not selected one.
+func (pi *PackageInfo) selectServices() {
+	if len(pi.ServiceNames) == 0 {
+		return
+	}
+
+	pi.Services = slices.DeleteFunc(pi.Services, func(s *Service) bool { return !slices.Contains(pi.ServiceNames, s.Name) })
+}
+
+// linkWithServices add method for services
+func (m *Method) linkWithServices(pi *PackageInfo, fdecl *ast.FuncDecl) (names []string) {
+	if !ast.IsExported(fdecl.Name.Name) {
+		return nil
+	}
+
+	fn, ok := pi.pkg.TypesInfo.Defs[fdecl.Name].(*types.Func)
+	if !ok {
+		return nil
+	}
+
+	// receiver can be pointer or not
+	recv := fn.Type().(*types.Signature).Recv().Type()
+	if p, ok := recv.(*types.Pointer); ok {
+		recv = p.Elem()
+	}
+
+	named, ok := recv.(*types.Named)
+	if !ok {
+		return nil
+	}
+
+	// find service in our service list
+	for _, s := range pi.Services {
+		if s.Name == named.Obj().Name() {
+			names = append(names, s.Name)
+			s.Methods = append(s.Methods, m)
+			break
+		}
+	}
+
 	return
 }
 
@@ -384,49 +759,63 @@ func (m *Method) parseArguments(pi *PackageInfo, fdecl *ast.FuncDecl, serviceNam
 			continue
 		}
 
-		// parse type
-		typeName := parseType(field.Type)
-		if typeName == "" {
-			// get argument names
-			fields := []string{}
-			for _, name := range field.Names {
-				fields = append(fields, name.Name)
-			}
+		// get argument names
+		fields := []string{}
+		for _, name := range field.Names {
+			fields = append(fields, name.Name)
+		}
 
-			// get Service.Method list
+		// get Service.Method list
+		methods := func() string {
 			methods := []string{}
 			for _, s := range serviceNames {
 				methods = append(methods, s+"."+m.Name)
 			}
-			return fmt.Errorf("Can't parse type of argument %s in %s", strings.Join(fields, ", "), strings.Join(methods, ", "))
+			return strings.Join(methods, ", ")
 		}
 
-		if typeName == contextTypeName {
+		// variadic argument is passed as array, type of argument variable is slice
+		t := pi.pkg.TypesInfo.Defs[field.Names[0]].Type()
+		_, isVariadic := field.Type.(*ast.Ellipsis)
+		if isContext(t) {
 			m.HasContext = true
 			continue // not add context to arg list
 		}
 
+		if unsupportedType(t) {
+			return fmt.Errorf("%s: unsupported type %s of argument %s in %s, it can't be encoded in JSON",
+				pi.pkg.Fset.Position(field.Type.Pos()), pi.exprString(field.Type), strings.Join(fields, ", "), methods())
+		}
+
+		// parse type
+		typeName := parseType(field.Type)
+		if isInlineStruct(t) {
+			typeName = pi.exprString(field.Type)
+		}
+
+		if typeName == "" {
+			return fmt.Errorf("%s: can't parse type of argument %s in %s",
+				pi.pkg.Fset.Position(field.Type.Pos()), strings.Join(fields, ", "), methods())
+		}
+
 		hasStar := hasStar(typeName) // check for pointer
-		smdType, itemType := parseSMDType(field.Type)
-
-		// find and save struct
-		s := parseStruct(field.Type)
-		var ref string
-		if s != nil {
-			ref = s.Name
-
-			// collect namespaces (imports)
-			if s.Namespace != "" {
-				if _, ok := pi.PackageNamesAndAliasesUsedInServices[s.Namespace]; !ok {
-					pi.PackageNamesAndAliasesUsedInServices[s.Namespace] = struct{}{}
-				}
+		smdType := pi.smdType(t)
+		description := pi.typeDescription(pi.pkg.TypesInfo, field.Type)
+
+		// inline struct is called by service, method and first argument name
+		if st, ok := types.Unalias(derefType(t)).(*types.Struct); ok {
+			typ := serviceNames[0] + "_" + m.Name + "_" + field.Names[0].Name
+			name := pi.namespace(pi.pkg.Types) + typ
+			if _, ok := pi.Structs[name]; !ok {
+				pi.Structs[name] = &Struct{Name: name, Namespace: pi.pkg.Name, Type: typ, typ: st, pkg: pi.pkg.Types}
 			}
 
-			if currentS, ok := pi.Structs[s.Name]; !ok || (currentS.StructType == nil && s.StructType != nil) {
-				pi.Structs[s.Name] = s
-			}
+			smdType = SMDType{Type: "Object", Ref: name}
 		}
 
+		// collect imports
+		m.imports = append(m.imports, pi.usedImports(field.Type)...)
+
 		// parse names
 		for _, name := range field.Names {
 			m.Args = append(m.Args, Arg{
@@ -435,11 +824,9 @@ func (m *Method) parseArguments(pi *PackageInfo, fdecl *ast.FuncDecl, serviceNam
 				CapitalName: strings.Title(name.Name),
 				JsonName:    lowerFirst(name.Name),
 				HasStar:     hasStar,
-				SMDType: SMDType{
-					Type:      smdType,
-					ItemsType: itemType,
-					Ref:       ref,
-				},
+				Variadic:    isVariadic,
+				Description: description,
+				SMDType:     smdType,
 			})
 		}
 	}
@@ -462,73 +849,120 @@ func (m *Method) parseReturns(pi *PackageInfo, fdecl *ast.FuncDecl, serviceNames
 	}
 
 	hasError := false
+	var values []*ast.Ident // names of value returns
 	for _, field := range fdecl.Type.Results.List {
-		if len(field.Names) > 1 {
-			return fmt.Errorf("%s contain more than one return arguments with same type", methods())
-		}
-
 		// parse type
 		typeName := parseType(field.Type)
 		if typeName == "" {
-			return fmt.Errorf("Can't parse type of return value in %s on position %d", methods(), len(m.Returns)+1)
+			return fmt.Errorf("%s: can't parse type of return value in %s on position %d",
+				pi.pkg.Fset.Position(field.Type.Pos()), methods(), len(m.Returns)+1)
 		}
 
-		var fieldName string
 		// get names if exist
-		if field.Names != nil {
-			fieldName = field.Names[0].Name
+		names := field.Names
+		if names == nil {
+			names = []*ast.Ident{nil}
 		}
 
-		m.Returns = append(m.Returns, Return{
-			Type: typeName,
-			Name: fieldName,
-		})
+		t := pi.pkg.TypesInfo.TypeOf(field.Type)
+		for _, name := range names {
+			var fieldName string
+			if name != nil {
+				fieldName = name.Name
+			}
+
+			m.Returns = append(m.Returns, Return{
+				Type:    typeName,
+				Name:    fieldName,
+				IsError: isError(t),
+			})
 
-		if typeName == "error" || typeName == errorTypeName || typeName == "*"+errorTypeName {
-			if hasError {
-				return fmt.Errorf("%s contain more than one error return arguments", methods())
+			if isError(t) {
+				if hasError {
+					return fmt.Errorf("%s contain more than one error return arguments", methods())
+				}
+				hasError = true
+				continue
 			}
-			hasError = true
-			continue
-		}
 
-		if m.SMDReturn != nil {
-			return fmt.Errorf("%s contain more than one variable return argument", methods())
-		}
+			if unsupportedType(t) {
+				return fmt.Errorf("%s: unsupported return type %s in %s, it can't be encoded in JSON",
+					pi.pkg.Fset.Position(field.Type.Pos()), typeName, methods())
+			}
 
-		hasStar := hasStar(typeName) // check for pointer
-		smdType, itemType := parseSMDType(field.Type)
+			values = append(values, name)
+			if len(values) > 1 {
+				continue
+			}
 
-		// find and save struct
-		s := parseStruct(field.Type)
-		var ref string
-		if s != nil {
-			ref = s.Name
+			hasStar := hasStar(typeName) // check for pointer
+			smdType := pi.smdType(t)
+			description := pi.typeDescription(pi.pkg.TypesInfo, field.Type)
 
-			if currentS, ok := pi.Structs[s.Name]; !ok || (currentS.StructType == nil && s.StructType != nil) {
-				pi.Structs[s.Name] = s
+			m.SMDReturn = &SMDReturn{
+				Name:        fieldName,
+				HasStar:     hasStar,
+				Description: description,
+				SMDType:     smdType,
+				typ:         t,
 			}
 		}
 
-		m.SMDReturn = &SMDReturn{
-			Name:    fieldName,
-			HasStar: hasStar,
-			SMDType: SMDType{
-				Type:      smdType,
-				ItemsType: itemType,
-				Ref:       ref,
-			},
+		// collect imports for client
+		if !isError(t) {
+			m.returns = append(m.returns, pi.usedImports(field.Type)...)
 		}
 	}
 
+	if len(values) < 2 {
+		return nil
+	}
+
+	// multiple values are packed into result object with return names as keys
+	fields := make([]*types.Var, 0, len(values))
+	tags := make([]string, 0, len(values))
+	fieldNames, keys := make(map[string]bool), make(map[string]bool)
+	for _, name := range values {
+		if name == nil || name.Name == "_" {
+			return fmt.Errorf("%s: %s contain more than one variable return argument, they should be named",
+				pi.pkg.Fset.Position(fdecl.Type.Results.Pos()), methods())
+		}
+
+		// result names are exported in result struct, e.g. a and A are both A
+		if fieldNames[strings.Title(name.Name)] || keys[name.Name] {
+			return fmt.Errorf("%s: return argument %s of %s conflicts with another return argument, they should differ not only in case",
+				pi.pkg.Fset.Position(name.Pos()), name.Name, methods())
+		}
+		fieldNames[strings.Title(name.Name)], keys[name.Name] = true, true
+
+		v := pi.pkg.TypesInfo.Defs[name].(*types.Var)
+		fields = append(fields, types.NewField(name.Pos(), pi.pkg.Types, strings.Title(name.Name), v.Type(), false))
+		tags = append(tags, fmt.Sprintf("json:%q", name.Name))
+	}
+
+	result := &Struct{
+		Namespace: pi.pkg.Name,
+		Type:      serviceNames[0] + "_" + m.Name + "Result",
+		typ:       types.NewStruct(fields, tags),
+		pkg:       pi.pkg.Types,
+	}
+	result.Name = pi.namespace(pi.pkg.Types) + result.Type
+	pi.Structs[result.Name] = result
+
+	m.ResultStruct = true
+	m.SMDReturn = &SMDReturn{
+		SMDType: SMDType{Type: "Object", Ref: result.Name},
+		typ:     result.typ,
+	}
+
 	return nil
 }
 
 // parseComments parse method comments and
-// fill default values, description for params and user errors map
-func (m *Method) parseComments(doc *ast.CommentGroup, pi *PackageInfo) {
+// fill default values, description and constraints for params and user errors map
+func (m *Method) parseComments(doc *ast.CommentGroup, pi *PackageInfo) error {
 	if doc == nil {
-		return
+		return nil
 	}
 
 	for _, comment := range doc.List {
@@ -540,9 +974,20 @@ func (m *Method) parseComments(doc *ast.CommentGroup, pi *PackageInfo) {
 		switch parseCommentType(line) {
 		case "argument":
 			name, alias, hasDefault, defaultValue, description := parseArgumentComment(line)
+			constraints, description, err := parseConstraints(description)
+			if err != nil {
+				return fmt.Errorf("%s argument %s: %s", m.Name, name, err)
+			}
+
 			for i, a := range m.Args {
 				if a.Name == name {
-					m.Args[i].Description = description
+					if description != "" {
+						m.Args[i].Description = description
+					}
+					m.Args[i].SMDType.Constraints = constraints
+					if err := m.Args[i].SMDType.validateEnum(); err != nil {
+						return fmt.Errorf("%s argument %s: %s", m.Name, name, err)
+					}
 
 					if hasDefault {
 						m.DefaultValues[name] = DefaultValue{
@@ -562,12 +1007,29 @@ func (m *Method) parseComments(doc *ast.CommentGroup, pi *PackageInfo) {
 				}
 			}
 		case "return":
-			m.SMDReturn.Description = parseReturnComment(line)
+			if m.SMDReturn == nil {
+				return fmt.Errorf("%s has no return value for comment %s", m.Name, comment.Text)
+			}
+
+			oneOf, description := parseOneOfComment(parseReturnComment(line))
+			if description != "" {
+				m.SMDReturn.Description = description
+			}
+
+			if len(oneOf) > 0 {
+				t, err := pi.oneOfType(oneOf, comment.Pos(), m.SMDReturn.typ)
+				if err != nil {
+					return fmt.Errorf("%s return: %s", m.Name, err)
+				}
+				m.SMDReturn.SMDType = t
+			}
 		case "error":
 			code, description := parseErrorComment(line)
 			m.Errors = append(m.Errors, SMDError{code, description})
 		}
 	}
+
+	return nil
 }
 
 func parseCommentType(line string) string {
@@ -591,6 +1053,17 @@ func parseReturnComment(line string) string {
 	return matches[1]
 }
 
+// parseOneOfComment returns comma separated type names of oneOf option and rest of return comment,
+// e.g. "oneOf=Circle,Square shape" returns [Circle Square] and "shape".
+func parseOneOfComment(line string) ([]string, string) {
+	if !strings.HasPrefix(line, oneOfOption) {
+		return nil, line
+	}
+
+	value, description, _ := strings.Cut(strings.TrimPrefix(line, oneOfOption), " ")
+	return strings.Split(value, ","), strings.TrimSpace(description)
+}
+
 func parseErrorComment(line string) (int, string) {
 	matches := errorCommentRegexp.FindStringSubmatch(line)
 	if len(matches) < 3 {
@@ -638,7 +1111,7 @@ func parseCommentGroup(doc *ast.CommentGroup) string {
 
 	result := ""
 	for _, comment := range doc.List {
-		if strings.HasPrefix(comment.Text, zenrpcMagicPrefix) {
+		if strings.HasPrefix(comment.Text, zenrpcMagicPrefix) || isZenrpcComment(comment.Text) {
 			continue
 		}
 
@@ -648,7 +1121,8 @@ func parseCommentGroup(doc *ast.CommentGroup) string {
 		result += strings.TrimSpace(strings.TrimPrefix(comment.Text, "//"))
 	}
 
-	return result
+	// empty line separates directives from doc in gofmt-ed comments
+	return strings.TrimRight(result, "\n")
 }
 
 func parseType(expr ast.Expr) string {
@@ -659,6 +1133,9 @@ func parseType(expr ast.Expr) string {
 		return parseType(v.X) + "." + v.Sel.Name
 	case *ast.ArrayType:
 		return "[" + parseType(v.Len) + "]" + parseType(v.Elt)
+	case *ast.Ellipsis:
+		// variadic argument
+		return "[]" + parseType(v.Elt)
 	case *ast.MapType:
 		return "map[" + parseType(v.Key) + "]" + parseType(v.Value)
 	case *ast.InterfaceType:
@@ -673,82 +1150,14 @@ func parseType(expr ast.Expr) string {
 	}
 }
 
-// Returned value will be used as smd.{Value} variable from smd package
-func parseSMDType(expr ast.Expr) (string, string) {
-	switch v := expr.(type) {
-	case *ast.StarExpr:
-		return parseSMDType(v.X)
-	case *ast.SelectorExpr, *ast.MapType, *ast.InterfaceType:
-		return "Object", ""
-	case *ast.ArrayType:
-		mainType, itemType := parseSMDType(v.Elt)
-		if itemType != "" {
-			return "Array", itemType
-		}
-
-		return "Array", mainType
-	case *ast.Ident:
-		switch v.Name {
-		case "bool":
-			return "Boolean", ""
-		case "string":
-			return "String", ""
-		case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64", "uintptr", "byte", "rune":
-			return "Integer", ""
-		case "float32", "float64", "complex64", "complex128":
-			return "Float", ""
-		default:
-			return "Object", "" // *ast.Ident contain type name, if type not basic then it struct or alias
-		}
-	default:
-		return "Object", "" // default complex type is object
+// exprString returns source code of expression from root package, e.g. inline struct type with tags.
+func (pi *PackageInfo) exprString(expr ast.Expr) string {
+	var b strings.Builder
+	if err := printer.Fprint(&b, pi.pkg.Fset, expr); err != nil {
+		return ""
 	}
-}
 
-// parseStruct find struct in type for display in SMD
-func parseStruct(expr ast.Expr) *Struct {
-	switch v := expr.(type) {
-	case *ast.StarExpr:
-		return parseStruct(v.X)
-	case *ast.SelectorExpr:
-		namespace := v.X.(*ast.Ident).Name
-		return &Struct{
-			Name:      namespace + "." + v.Sel.Name,
-			Namespace: namespace,
-			Type:      v.Sel.Name,
-		}
-	case *ast.ArrayType:
-		// will get last type
-		return parseStruct(v.Elt)
-	case *ast.MapType:
-		// will get last type
-		return parseStruct(v.Value)
-	case *ast.Ident:
-		switch v.Name {
-		case "bool", "string",
-			"int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64", "uintptr", "byte", "rune",
-			"float32", "float64", "complex64", "complex128":
-			return nil
-		}
-
-		s := &Struct{
-			Name:      v.Name,
-			Namespace: ".",
-			Type:      v.Name,
-		}
-
-		if v.Obj != nil && v.Obj.Decl != nil {
-			if ts, ok := v.Obj.Decl.(*ast.TypeSpec); ok {
-				if st, ok := ts.Type.(*ast.StructType); ok {
-					s.StructType = st
-				}
-			}
-		}
-
-		return s
-	default:
-		return nil
-	}
+	return b.String()
 }
 
 func hasZenrpcComment(spec *ast.TypeSpec) bool {
@@ -759,19 +1168,30 @@ func hasZenrpcComment(spec *ast.TypeSpec) bool {
 	return false
 }
 
-func hasZenrpcService(structType *ast.StructType) bool {
-	if structType.Fields.List == nil {
+// hasZenrpcDoc checks that doc comment has //zenrpc line.
+func hasZenrpcDoc(doc *ast.CommentGroup) bool {
+	if doc == nil {
 		return false
 	}
 
-	for _, field := range structType.Fields.List {
-		selectorExpr, ok := field.Type.(*ast.SelectorExpr)
-		if !ok {
-			continue
+	for _, comment := range doc.List {
+		if isZenrpcComment(comment.Text) {
+			return true
 		}
+	}
 
-		x, ok := selectorExpr.X.(*ast.Ident)
-		if ok && selectorExpr.Sel != nil && x.Name+"."+selectorExpr.Sel.Name == zenrpcService {
+	return false
+}
+
+// isZenrpcComment checks that comment is //zenrpc mark, also in "// zenrpc" form produced by gofmt.
+func isZenrpcComment(text string) bool {
+	return "//"+strings.TrimSpace(strings.TrimPrefix(text, "//")) == zenrpcComment
+}
+
+// hasZenrpcService checks that struct embeds zenrpc.Service.
+func (pi *PackageInfo) hasZenrpcService(structType *ast.StructType) bool {
+	for _, field := range structType.Fields.List {
+		if len(field.Names) == 0 && isNamed(pi.pkg.TypesInfo.TypeOf(field.Type), zenrpcPackagePath, "Service") {
 			return true
 		}
 	}
@@ -1,6 +1,17 @@
 package parser
 
-import "testing"
+import (
+	"fmt"
+	"go/ast"
+	goparser "go/parser"
+	"go/token"
+	"go/types"
+	"golang.org/x/tools/go/packages"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
 
 func Test_parseArgumentComment(t *testing.T) {
 	tests := []struct {
@@ -192,3 +203,961 @@ func Test_parseCommentType(t *testing.T) {
 		})
 	}
 }
+
+func Test_smdType(t *testing.T) {
+	fset := token.NewFileSet()
+	timePkg := checkPackage(t, fset, "time", `package time
+
+type Time struct{ wall uint64 }
+`)
+
+	modelPkg := checkPackage(t, fset, "example.com/model", `package model
+
+type Point struct{ X, Y int }
+
+type IDs []ID
+
+type ID = UserID
+
+type UserID int64
+
+type Amount int64
+`)
+
+	rootPkg := checkPackage(t, fset, "example.com/root", `package root
+
+import (
+	"example.com/model"
+	"time"
+)
+
+type Point struct{}
+
+type Status string
+
+const (
+	StatusNew  Status = "new"
+	StatusDone Status = "done"
+)
+
+type Tree map[string]Tree
+
+type Location = model.Point
+
+type Page[T any] struct{ Items []T }
+
+type Event struct{ At time.Time }
+
+type Level int
+
+func (Level) MarshalText() ([]byte, error) { return nil, nil }
+
+type Raw struct{}
+
+func (*Raw) MarshalJSON() ([]byte, error) { return nil, nil }
+
+//zenrpc:type string:uuid
+type UUID [16]byte
+`, modelPkg.Types, timePkg.Types)
+
+	tests := []struct {
+		test string
+		expr string
+		pkg  *types.Package
+		want SMDType
+	}{
+		{
+			test: "should parse basic type",
+			expr: "*uint64",
+			want: SMDType{Type: "Integer"},
+		},
+		{
+			test: "should parse nested arrays",
+			expr: "[][]float64",
+			want: SMDType{Type: "Array", Items: &SMDType{Type: "Array", Items: &SMDType{Type: "Float"}}},
+		},
+		{
+			test: "should parse map of structs",
+			expr: "map[string][]*Point",
+			want: SMDType{Type: "Object", Values: &SMDType{Type: "Array", Items: &SMDType{Type: "Object", Ref: "root.Point"}}},
+		},
+		{
+			test: "should parse struct from another package",
+			expr: "Point",
+			pkg:  modelPkg.Types,
+			want: SMDType{Type: "Object", Ref: "model.Point"},
+		},
+		{
+			test: "should parse struct with selector",
+			expr: "model.Point",
+			want: SMDType{Type: "Object", Ref: "model.Point"},
+		},
+		{
+			test: "should resolve named types and aliases",
+			expr: "model.IDs",
+			want: SMDType{Type: "Array", Items: &SMDType{Type: "Integer"}},
+		},
+		{
+			test: "should resolve enum",
+			expr: "[]Status",
+			want: SMDType{Type: "Array", Items: &SMDType{Type: "String", Enum: []string{"new", "done"}}},
+		},
+		{
+			test: "should stop on recursive type",
+			expr: "Tree",
+			want: SMDType{Type: "Object", Values: &SMDType{Type: "Object"}},
+		},
+		{
+			test: "should resolve alias to struct",
+			expr: "Location",
+			want: SMDType{Type: "Object", Ref: "model.Point"},
+		},
+		{
+			test: "should map time.Time to date-time string",
+			expr: "[]*time.Time",
+			want: SMDType{Type: "Array", Items: &SMDType{Type: "String", Format: "date-time"}},
+		},
+		{
+			test: "should map []byte to base64 string",
+			expr: "[]byte",
+			want: SMDType{Type: "String", ContentEncoding: "base64"},
+		},
+		{
+			test: "should map text marshaler to string",
+			expr: "Level",
+			want: SMDType{Type: "String"},
+		},
+		{
+			test: "should map json marshaler to any",
+			expr: "Raw",
+			want: SMDType{Type: "Any"},
+		},
+		{
+			test: "should map type with magic comment",
+			expr: "UUID",
+			want: SMDType{Type: "String", Format: "uuid"},
+		},
+		{
+			test: "should map type with custom mapping",
+			expr: "model.Amount",
+			want: SMDType{Type: "String", Format: "decimal"},
+		},
+		{
+			test: "should parse empty interface as any",
+			expr: "map[string]interface{}",
+			want: SMDType{Type: "Object", Values: &SMDType{Type: "Any"}},
+		},
+		{
+			test: "should parse map of struct slices",
+			expr: "map[string][][]model.Point",
+			want: SMDType{Type: "Object", Values: &SMDType{Type: "Array", Items: &SMDType{Type: "Array", Items: &SMDType{Type: "Object", Ref: "model.Point"}}}},
+		},
+		{
+			test: "should name generic struct by type arguments",
+			expr: "Page[Point]",
+			want: SMDType{Type: "Object", Ref: "root.Page_Point"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.test, func(t *testing.T) {
+			pkg := tt.pkg
+			if pkg == nil {
+				pkg = rootPkg.Types
+			}
+
+			// evaluate in file scope with imports
+			tv, err := types.Eval(fset, pkg, pkg.Scope().Child(0).Pos(), tt.expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			pi := &PackageInfo{
+				PackagePath: rootPkg.PkgPath,
+				Structs:     make(map[string]*Struct),
+				TypeMappings: map[string]SMDType{
+					"example.com/model.Amount": {Type: "String", Format: "decimal"},
+				},
+				packages: map[*types.Package]*packages.Package{
+					timePkg.Types:  timePkg,
+					modelPkg.Types: modelPkg,
+					rootPkg.Types:  rootPkg,
+				},
+				indexed:      make(map[*types.Package]bool),
+				fields:       make(map[token.Pos]*ast.Field),
+				typeDocs:     make(map[*types.TypeName]*ast.CommentGroup),
+				enums:        make(map[*types.TypeName][]string),
+				typeComments: make(map[*types.TypeName]SMDType),
+				resolving:    make(map[*types.TypeName]bool),
+			}
+
+			got := pi.smdType(tv.Type)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("smdType() = %+v, want %+v", got, tt.want)
+			}
+
+			if _, ok := pi.Structs[got.Ref]; got.Ref != "" && !ok {
+				t.Errorf("struct %s is not collected", got.Ref)
+			}
+		})
+	}
+}
+
+func TestPackageInfo_parseStructs(t *testing.T) {
+	fset := token.NewFileSet()
+	modelPkg := checkPackage(t, fset, "example.com/model", `package model
+
+type Edge struct {
+	From *Vertex
+	To   []Vertex
+}
+
+type Vertex struct {
+	Edges map[string]Edge
+}
+
+type A struct {
+	*B
+	X int
+}
+
+type B struct {
+	*A
+	Y int
+}
+`)
+
+	rootPkg := checkPackage(t, fset, "example.com/root", `package root
+
+import "example.com/model"
+
+type Graph struct {
+	Root   *model.Vertex
+	Parent *Graph
+	Pair   model.A
+}
+`, modelPkg.Types)
+
+	pi := &PackageInfo{
+		PackagePath: rootPkg.PkgPath,
+		Structs:     make(map[string]*Struct),
+		packages: map[*types.Package]*packages.Package{
+			modelPkg.Types: modelPkg,
+			rootPkg.Types:  rootPkg,
+		},
+		indexed:      make(map[*types.Package]bool),
+		fields:       make(map[token.Pos]*ast.Field),
+		typeDocs:     make(map[*types.TypeName]*ast.CommentGroup),
+		enums:        make(map[*types.TypeName][]string),
+		typeComments: make(map[*types.TypeName]SMDType),
+		resolving:    make(map[*types.TypeName]bool),
+	}
+
+	graph := pi.smdType(rootPkg.Types.Scope().Lookup("Graph").Type())
+	if err := pi.parseStructs(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		want []Property
+	}{
+		{
+			name: "root.Graph",
+			want: []Property{
+				{Name: "Root", Optional: true, Nullable: true, SMDType: SMDType{Type: "Object", Ref: "model.Vertex"}},
+				{Name: "Parent", Optional: true, Nullable: true, SMDType: SMDType{Type: "Object", Ref: "root.Graph"}},
+				{Name: "Pair", SMDType: SMDType{Type: "Object", Ref: "model.A"}},
+			},
+		},
+		{
+			name: "model.Vertex",
+			want: []Property{
+				{Name: "Edges", SMDType: SMDType{Type: "Object", Values: &SMDType{Type: "Object", Ref: "model.Edge"}}},
+			},
+		},
+		{
+			name: "model.Edge",
+			want: []Property{
+				{Name: "From", Optional: true, Nullable: true, SMDType: SMDType{Type: "Object", Ref: "model.Vertex"}},
+				{Name: "To", SMDType: SMDType{Type: "Array", Items: &SMDType{Type: "Object", Ref: "model.Vertex"}}},
+			},
+		},
+		{
+			name: "model.A",
+			want: []Property{
+				{Name: "Y", SMDType: SMDType{Type: "Integer"}},
+				{Name: "X", SMDType: SMDType{Type: "Integer"}},
+			},
+		},
+		{
+			name: "model.B",
+			want: []Property{
+				{Name: "X", SMDType: SMDType{Type: "Integer"}},
+				{Name: "Y", SMDType: SMDType{Type: "Integer"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, ok := pi.Structs[tt.name]
+			if !ok {
+				t.Fatalf("struct %s is not collected", tt.name)
+			}
+
+			if !reflect.DeepEqual(s.Properties, tt.want) {
+				t.Errorf("Properties = %+v, want %+v", s.Properties, tt.want)
+			}
+		})
+	}
+
+	var names []string
+	for _, s := range usedStructs([]*SMDType{&graph}, pi.Structs) {
+		names = append(names, s.Name)
+	}
+
+	if want := []string{"root.Graph", "model.Vertex", "model.Edge", "model.A"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("usedStructs() = %v, want %v", names, want)
+	}
+}
+
+func TestPackageInfo_addStruct(t *testing.T) {
+	fset := token.NewFileSet()
+	first := checkPackage(t, fset, "example.com/a/model", "package model\n\ntype Point struct{ X, Y int }\n")
+	second := checkPackage(t, fset, "example.com/b/model", "package model\n\ntype Point struct{ Lat, Lon float64 }\n")
+
+	pi := &PackageInfo{PackagePath: "example.com/root", Structs: make(map[string]*Struct)}
+	point := first.Types.Scope().Lookup("Point").Type().(*types.Named)
+	if name := pi.addStruct(point); name != "model.Point" || pi.addStruct(point) != name || pi.err != nil {
+		t.Fatalf("addStruct() = %s, err %v", name, pi.err)
+	}
+
+	pi.addStruct(second.Types.Scope().Lookup("Point").Type().(*types.Named))
+	if pi.err == nil || !strings.Contains(pi.err.Error(), "example.com/a/model.Point") {
+		t.Errorf("got err %v, want conflict of model.Point", pi.err)
+	}
+}
+
+func TestStruct_properties(t *testing.T) {
+	fset := token.NewFileSet()
+	pkg := checkPackage(t, fset, "example.com/root", `package root
+
+type Base struct {
+	ID     int
+	Name   string `+"`json:\"name\"`"+`
+	Hidden string
+}
+
+type Other struct {
+	ID     int
+	Hidden string
+}
+
+type meta struct {
+	Version int `+"`json:\"version,string\"`"+`
+}
+
+type Label string
+
+type Ext struct{ A int }
+
+type Kind string
+
+const KindNew Kind = "new"
+
+type Level int
+
+const LevelHigh Level = 2
+
+type Item struct {
+	Base
+	*Other
+	meta
+	Label
+	Title string  `+"`json:\"name\"`"+`
+	Count int     `+"`json:\"count,omitempty\"`"+`
+	Price float64 `+"`json:\",string\"`"+`
+	Kind  Kind    `+"`json:\"kind,string\"`"+`
+	Level *Level  `+"`json:\"level,string\"`"+`
+	Next  *Item   `+"`json:\"next\"`"+`
+	Ext   `+"`json:\"ext\"`"+`
+	Skip  int `+"`json:\"-\"`"+`
+	Dash  int `+"`json:\"-,\"`"+`
+}
+`)
+
+	pi := &PackageInfo{
+		PackagePath:  pkg.PkgPath,
+		Structs:      make(map[string]*Struct),
+		packages:     map[*types.Package]*packages.Package{pkg.Types: pkg},
+		indexed:      make(map[*types.Package]bool),
+		fields:       make(map[token.Pos]*ast.Field),
+		typeDocs:     make(map[*types.TypeName]*ast.CommentGroup),
+		enums:        make(map[*types.TypeName][]string),
+		typeComments: make(map[*types.TypeName]SMDType),
+		resolving:    make(map[*types.TypeName]bool),
+	}
+
+	item := pi.Structs[pi.addStruct(pkg.Types.Scope().Lookup("Item").Type().(*types.Named))]
+	if err := item.parse(pi); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Property{
+		{Name: "version", SMDType: SMDType{Type: "String"}},
+		{Name: "Label", SMDType: SMDType{Type: "String"}},
+		{Name: "name", SMDType: SMDType{Type: "String"}},
+		{Name: "count", Optional: true, SMDType: SMDType{Type: "Integer"}},
+		{Name: "Price", SMDType: SMDType{Type: "String"}},
+		{Name: "kind", SMDType: SMDType{Type: "String", Enum: []string{`"new"`}}},
+		{Name: "level", Optional: true, Nullable: true, SMDType: SMDType{Type: "String", Enum: []string{"2"}}},
+		{Name: "next", Optional: true, Nullable: true, SMDType: SMDType{Type: "Object", Ref: "root.Item"}},
+		{Name: "ext", SMDType: SMDType{Type: "Object", Ref: "root.Ext"}},
+		{Name: "-", SMDType: SMDType{Type: "Integer"}},
+	}
+
+	if !reflect.DeepEqual(item.Properties, want) {
+		t.Errorf("Properties = %+v, want %+v", item.Properties, want)
+	}
+}
+
+func Test_unsupportedType(t *testing.T) {
+	fset := token.NewFileSet()
+	pkg := checkPackage(t, fset, "example.com/root", `package root
+
+type Handler func()
+
+type Tree map[string]Tree
+
+type Events chan string
+
+func (Events) MarshalJSON() ([]byte, error) { return nil, nil }
+`)
+
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{expr: "chan int", want: true},
+		{expr: "[]*Handler", want: true},
+		{expr: "map[string]complex128", want: true},
+		{expr: "struct{ C chan int }", want: false},
+		{expr: "Tree", want: false},
+		{expr: "Events", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			tv, err := types.Eval(fset, pkg.Types, token.NoPos, tt.expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := unsupportedType(tv.Type); got != tt.want {
+				t.Errorf("unsupportedType() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// checkPackage type checks package source with given imports.
+func checkPackage(t *testing.T, fset *token.FileSet, path, src string, imports ...*types.Package) *packages.Package {
+	f, err := goparser.ParseFile(fset, path+".go", src, goparser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conf := types.Config{Importer: importerFunc(func(path string) (*types.Package, error) {
+		for _, pkg := range imports {
+			if pkg.Path() == path {
+				return pkg, nil
+			}
+		}
+		return nil, fmt.Errorf("package %s not found", path)
+	})}
+
+	info := &types.Info{Defs: make(map[*ast.Ident]types.Object), Uses: make(map[*ast.Ident]types.Object)}
+	pkg, err := conf.Check(path, fset, []*ast.File{f}, info)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &packages.Package{PkgPath: path, Types: pkg, Syntax: []*ast.File{f}, TypesInfo: info}
+}
+
+type importerFunc func(path string) (*types.Package, error)
+
+func (f importerFunc) Import(path string) (*types.Package, error) { return f(path) }
+
+func Test_parseConstraints(t *testing.T) {
+	tests := []struct {
+		test     string
+		line     string
+		want     Constraints
+		wantRest string
+		wantErr  bool
+	}{
+		{
+			test:     "should parse description without constraints",
+			line:     "page size",
+			wantRest: "page size",
+		},
+		{
+			test:     "should parse range and description",
+			line:     "min=1 max=100.5 page size",
+			want:     Constraints{Minimum: "1", Maximum: "100.5"},
+			wantRest: "page size",
+		},
+		{
+			test: "should parse lengths, quoted pattern and enum",
+			line: "minLength=1 maxLength=10 minItems=2 maxItems=3 pattern=`^[a-z ]+$` enum=a,b",
+			want: Constraints{MinLength: 1, MaxLength: 10, MinItems: 2, MaxItems: 3, Pattern: "^[a-z ]+$", Enum: []string{"a", "b"}},
+		},
+		{
+			test:    "should fail on invalid number",
+			line:    "min=one",
+			wantErr: true,
+		},
+		{
+			test:    "should fail on infinite number",
+			line:    "min=-Inf",
+			wantErr: true,
+		},
+		{
+			test:    "should fail on not a number",
+			line:    "max=NaN",
+			wantErr: true,
+		},
+		{
+			test:    "should fail on invalid pattern",
+			line:    "pattern=[a-",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.test, func(t *testing.T) {
+			got, gotRest, err := parseConstraints(tt.line)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseConstraints() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseConstraints() got = %+v, want %+v", got, tt.want)
+			}
+			if gotRest != tt.wantRest {
+				t.Errorf("parseConstraints() gotRest = %v, want %v", gotRest, tt.wantRest)
+			}
+		})
+	}
+}
+
+func Test_parseOneOfComment(t *testing.T) {
+	tests := []struct {
+		line            string
+		wantTypes       []string
+		wantDescription string
+	}{
+		{line: "result", wantDescription: "result"},
+		{line: "oneOf=Circle,model.Point", wantTypes: []string{"Circle", "model.Point"}},
+		{line: "oneOf=Circle,Square  shape of figure", wantTypes: []string{"Circle", "Square"}, wantDescription: "shape of figure"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			gotTypes, gotDescription := parseOneOfComment(tt.line)
+			if !reflect.DeepEqual(gotTypes, tt.wantTypes) || gotDescription != tt.wantDescription {
+				t.Errorf("parseOneOfComment() got = %v, %q, want %v, %q", gotTypes, gotDescription, tt.wantTypes, tt.wantDescription)
+			}
+		})
+	}
+}
+
+func Test_parseEnumComment(t *testing.T) {
+	tests := []struct {
+		test   string
+		doc    []string
+		want   []string
+		wantOk bool
+	}{
+		{
+			test: "should skip regular comments",
+			doc:  []string{"// Status is a status."},
+		},
+		{
+			test:   "should parse enum without values",
+			doc:    []string{"// Status is a status.", "//zenrpc:enum"},
+			wantOk: true,
+		},
+		{
+			test:   "should parse enum values",
+			doc:    []string{"//zenrpc:enum new,done"},
+			want:   []string{"new", "done"},
+			wantOk: true,
+		},
+		{
+			test: "should skip other magic comments",
+			doc:  []string{"//zenrpc:enumeration a,b"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.test, func(t *testing.T) {
+			doc := &ast.CommentGroup{}
+			for _, line := range tt.doc {
+				doc.List = append(doc.List, &ast.Comment{Text: line})
+			}
+
+			got, gotOk := parseEnumComment(doc)
+			if !reflect.DeepEqual(got, tt.want) || gotOk != tt.wantOk {
+				t.Errorf("parseEnumComment() got = %v, %v, want %v, %v", got, gotOk, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func Test_validateEnumComment(t *testing.T) {
+	fset := token.NewFileSet()
+	pkg := checkPackage(t, fset, "example.com/root", `package root
+
+type Status string
+
+type Priority int
+
+type Level uint8
+
+type Ratio float64
+
+type Point struct{ X, Y int }
+`)
+
+	tests := []struct {
+		typ     string
+		values  []string
+		wantErr string
+	}{
+		{typ: "Status", values: []string{"new", "1"}},
+		{typ: "Priority", values: []string{"-1", "2"}},
+		{typ: "Priority", values: []string{"1", "high"}, wantErr: `invalid enum value "high": int expected`},
+		{typ: "Level", values: []string{"-1"}, wantErr: `invalid enum value "-1": uint8 expected`},
+		{typ: "Ratio", values: []string{"0.5", "x"}, wantErr: `invalid enum value "x": float64 expected`},
+		{typ: "Ratio", values: []string{"NaN"}, wantErr: `invalid enum value "NaN": float64 expected`},
+		{typ: "Point", values: []string{"a"}, wantErr: "enum is not supported for struct{X int; Y int} type"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.typ, func(t *testing.T) {
+			err := validateEnumComment(pkg.Types.Scope().Lookup(tt.typ).(*types.TypeName), tt.values)
+			if gotErr := fmt.Sprint(err); err != nil && gotErr != tt.wantErr || err == nil && tt.wantErr != "" {
+				t.Errorf("validateEnumComment() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSMDType_validateEnum(t *testing.T) {
+	tests := []struct {
+		typ     SMDType
+		wantErr bool
+	}{
+		{typ: SMDType{Type: "Integer", Constraints: Constraints{Enum: []string{"1", "-2", "18446744073709551615"}}}},
+		{typ: SMDType{Type: "Integer", Constraints: Constraints{Enum: []string{"1", "1.5"}}}, wantErr: true},
+		{typ: SMDType{Type: "Float", Constraints: Constraints{Enum: []string{"1", "1.5"}}}},
+		{typ: SMDType{Type: "Float", Constraints: Constraints{Enum: []string{"Inf"}}}, wantErr: true},
+		{typ: SMDType{Type: "Boolean", Constraints: Constraints{Enum: []string{"yes"}}}, wantErr: true},
+		{typ: SMDType{Type: "Object", Constraints: Constraints{Enum: []string{"a"}}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.typ.Type, func(t *testing.T) {
+			if err := tt.typ.validateEnum(); (err != nil) != tt.wantErr {
+				t.Errorf("validateEnum() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSMDType_quoted(t *testing.T) {
+	tests := []struct {
+		typ  SMDType
+		want SMDType
+	}{
+		{
+			typ:  SMDType{Type: "Integer", Enum: []string{"1", "2"}},
+			want: SMDType{Type: "String", Enum: []string{"1", "2"}},
+		},
+		{
+			typ:  SMDType{Type: "Float", Enum: []string{"1e+06", "0.5"}, Constraints: Constraints{Enum: []string{"1e21"}}},
+			want: SMDType{Type: "String", Enum: []string{"1000000", "0.5"}, Constraints: Constraints{Enum: []string{"1e+21"}}},
+		},
+		{
+			typ:  SMDType{Type: "String", Enum: []string{"new"}},
+			want: SMDType{Type: "String", Enum: []string{`"new"`}},
+		},
+		{
+			typ:  SMDType{Type: "Array", Items: &SMDType{Type: "Integer"}},
+			want: SMDType{Type: "Array", Items: &SMDType{Type: "Integer"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.typ.Type, func(t *testing.T) {
+			if got := tt.typ.quoted(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("quoted() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadPackages(t *testing.T) {
+	pis, err := LoadPackages(nil, "../testdata", "../testdata/subservice", "../testdata/model")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	services := map[string]int{}
+	for _, pi := range pis {
+		if err := pi.ParsePackage(); err != nil {
+			t.Fatalf("%s: %s", pi.PackagePath, err)
+		}
+
+		services[pi.PackageName] = len(pi.Services)
+	}
+
+	want := map[string]int{"testdata": 6, "subarithservice": 1, "model": 0}
+	if !reflect.DeepEqual(services, want) {
+		t.Errorf("services by package = %v, want %v", services, want)
+	}
+
+	for _, pi := range pis {
+		if pi.PackageName == "subarithservice" && !strings.HasSuffix(pi.OutputFilename(), "/testdata/subservice/subarithservice_zenrpc.go") {
+			t.Errorf("got output filename %s", pi.OutputFilename())
+		}
+	}
+}
+
+func TestPackageInfo_ServiceNames(t *testing.T) {
+	pi, err := NewPackageInfo("../testdata/arith.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pi.ServiceNames = []string{"PrintService", "AdminPhoneBook"}
+	pi.OutputFile = "../testdata/arith_zenrpc.go"
+	if err := pi.Parse("../testdata/arith.go"); err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, s := range pi.Services {
+		names = append(names, s.Name)
+	}
+
+	// declaration order is kept, promoted methods of not selected PhoneBook are generated
+	if want := []string{"AdminPhoneBook", "PrintService"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("services = %v, want %v", names, want)
+	}
+
+	if len(pi.Services[0].Methods) != 8 {
+		t.Errorf("got %d AdminPhoneBook methods", len(pi.Services[0].Methods))
+	}
+
+	// model package is used by ArithService only
+	if len(pi.ImportsIncludedToGeneratedCode) != 0 || len(pi.ImportsIncludedToGeneratedClient) != 0 {
+		t.Errorf("got imports %v and %v", pi.ImportsIncludedToGeneratedCode, pi.ImportsIncludedToGeneratedClient)
+	}
+
+	if pi.OutputFilename() != "../testdata/arith_zenrpc.go" {
+		t.Errorf("got output filename %s", pi.OutputFilename())
+	}
+}
+
+func TestPackageInfo_Parse(t *testing.T) {
+	pi, err := NewPackageInfo("../testdata/arith.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := pi.Parse("../testdata/arith.go"); err != nil {
+		t.Fatal(err)
+	}
+
+	// embedded struct from indirectly imported package
+	point, ok := pi.Structs["model.Point"]
+	if !ok {
+		t.Fatal("model.Point is not collected")
+	}
+
+	var names []string
+	for _, p := range point.Properties {
+		names = append(names, p.Name)
+	}
+
+	if want := []string{"Name", "SomeField", "Measure", "X", "Y", "ConnectedObject"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("model.Point properties = %v, want %v", names, want)
+	}
+
+	// only imports used in service methods
+	var imports []string
+	for _, i := range pi.ImportsIncludedToGeneratedCode {
+		imports = append(imports, i.Path.Value)
+	}
+
+	if want := []string{`"github.com/semrush/zenrpc/v2/testdata/model"`}; !reflect.DeepEqual(imports, want) {
+		t.Errorf("imports = %v, want %v", imports, want)
+	}
+
+	// own methods go before promoted ones, own Echo shadows promoted one
+	for _, s := range pi.Services {
+		if s.Name != "AdminPhoneBook" {
+			continue
+		}
+
+		var methods []string
+		for _, m := range s.Methods {
+			methods = append(methods, m.Name)
+		}
+
+		if want := []string{"Purge", "Echo", "Get", "ValidateSearch", "ById", "Delete", "Remove", "Save"}; !reflect.DeepEqual(methods, want) {
+			t.Errorf("AdminPhoneBook methods = %v, want %v", methods, want)
+		}
+	}
+
+	// interface service is served by generated invoker and implemented by client
+	for _, s := range pi.Services {
+		if s.Name != "Contacts" {
+			continue
+		}
+
+		if !s.Interface || s.InvokerName() != "ContactsInvoker" || !s.ClientImplements() {
+			t.Errorf("Contacts interface = %v, invoker = %s, client implements = %v", s.Interface, s.InvokerName(), s.ClientImplements())
+		}
+
+		if len(s.Methods) != 2 || s.Methods[0].Name != "Find" || !s.Methods[0].HasContext || s.Methods[0].Args[0].Description != "name prefix" {
+			t.Errorf("got Contacts methods %+v", s.Methods)
+		}
+	}
+
+	// variadic and inline struct arguments
+	for _, s := range pi.Services {
+		for _, m := range s.Methods {
+			if s.Name != "CatalogueService" || m.Name != "Fifth" {
+				continue
+			}
+
+			if filter := m.Args[0]; filter.SMDType.Ref != "testdata.CatalogueService_Fifth_filter" || !strings.HasPrefix(filter.Type, "struct {") {
+				t.Errorf("filter argument = %+v", filter)
+			}
+
+			if ids := m.Args[1]; !ids.Variadic || ids.Type != "[]int" || ids.SMDType.Type != "Array" {
+				t.Errorf("ids argument = %+v", ids)
+			}
+
+			if _, ok := pi.Structs["testdata.CatalogueService_Fifth_filter"]; !ok {
+				t.Error("inline struct argument is not collected")
+			}
+		}
+	}
+}
+
+func TestPackageInfo_ParseCrossPackageEmbedding(t *testing.T) {
+	filename, err := filepath.Abs("testdata/crosspkg/service.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pi, err := NewPackageInfo(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = pi.Parse(filename)
+	if err == nil || !strings.Contains(err.Error(), "github.com/semrush/zenrpc/v2/testdata/subservice.SubArithService") {
+		t.Errorf("Parse() error = %v, want error about embedded SubArithService", err)
+	}
+}
+
+func TestPackageInfo_ParsePackageErrors(t *testing.T) {
+	filename, err := filepath.Abs("testdata/broken/service.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pi, err := NewPackageInfo(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = pi.Parse(filename)
+	if err == nil || !strings.Contains(err.Error(), "service.go:15:28: undefined: Undefinedd") {
+		t.Errorf("Parse() error = %v, want undefined Undefinedd with position", err)
+	}
+	if err != nil && strings.Count(err.Error(), "\n") != 1 {
+		t.Errorf("Parse() error = %v, errors of generated files and generated declarations should be skipped", err)
+	}
+}
+
+func TestPackageInfo_ParseConflictingResults(t *testing.T) {
+	filename, err := filepath.Abs("testdata/results/service.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pi, err := NewPackageInfo(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = pi.Parse(filename)
+	if err == nil || !strings.Contains(err.Error(), "service.go:8:37: return argument A of ResultsService.Get conflicts") {
+		t.Errorf("Parse() error = %v, want conflict of return argument A with position", err)
+	}
+}
+
+func TestParseTypeMapping(t *testing.T) {
+	tests := []struct {
+		test     string
+		mapping  string
+		wantName string
+		want     SMDType
+		wantErr  bool
+	}{
+		{
+			test:     "should parse type",
+			mapping:  "time.Duration=integer",
+			wantName: "time.Duration",
+			want:     SMDType{Type: "Integer"},
+		},
+		{
+			test:     "should parse type with format",
+			mapping:  "github.com/google/uuid.UUID=string:uuid",
+			wantName: "github.com/google/uuid.UUID",
+			want:     SMDType{Type: "String", Format: "uuid"},
+		},
+		{
+			test:    "should fail without package",
+			mapping: "Duration=integer",
+			wantErr: true,
+		},
+		{
+			test:    "should fail on unknown type",
+			mapping: "time.Duration=int",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.test, func(t *testing.T) {
+			gotName, got, err := ParseTypeMapping(tt.mapping)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseTypeMapping() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if gotName != tt.wantName || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseTypeMapping() got = %v, %+v, want %v, %+v", gotName, got, tt.wantName, tt.want)
+			}
+		})
+	}
+}
@@ -1,224 +1,425 @@
 package parser
 
 import (
-	"go/ast"
+	"fmt"
+	"go/types"
 	"reflect"
+	"sort"
 	"strings"
+	"unicode"
 )
 
-func (pi *PackageInfo) parseStructs() {
-	for _, s := range pi.Structs {
-		s.parse(pi)
-	}
-}
-
-func (s *Struct) findTypeSpec(pi *PackageInfo) bool {
-	if s.StructType != nil {
-		return true
-	}
+// parseStructs parses all collected structs, including structs found during parsing.
+func (pi *PackageInfo) parseStructs() error {
+	for parsed := false; !parsed; {
+		parsed = true
+		for _, s := range pi.Structs {
+			if s.Properties != nil {
+				continue
+			}
 
-	for _, f := range pi.Scopes[s.Namespace] {
-		if obj, ok := f.Objects[s.Type]; ok && obj.Decl != nil {
-			if ts, ok := obj.Decl.(*ast.TypeSpec); ok {
-				if st, ok := ts.Type.(*ast.StructType); ok {
-					s.StructType = st
-					return true
-				}
+			parsed = false
+			if err := s.parse(pi); err != nil {
+				return err
 			}
 		}
 	}
 
-	return false
+	return nil
 }
 
 func (s *Struct) parse(pi *PackageInfo) error {
-	if !s.findTypeSpec(pi) || s.Properties != nil {
-		// can't find struct implementation
-		// or struct already parsed
+	if s.Properties != nil {
+		// struct already parsed
 		return nil
 	}
 
-	s.Properties = []Property{}
-	for _, field := range s.StructType.Fields.List {
-		tag := parseJsonTag(field.Tag)
+	properties, err := s.properties(pi)
+	if err != nil {
+		return err
+	}
 
-		// do not parse tags that ignored in json
-		if tag == "-" {
-			continue
-		}
+	s.Properties = properties
+	return nil
+}
+
+// jsonField is struct field visible in JSON.
+type jsonField struct {
+	Property
+	tagged bool  // name is taken from json tag
+	index  []int // field index sequence, like in reflect.StructField.Index
+}
 
-		// parse embedded struct
-		if field.Names == nil {
-			if embeddedS := parseStruct(field.Type); embeddedS != nil {
-				// set right namespace for struct from another package
-				if embeddedS.Namespace == "." && s.Namespace != "." {
-					embeddedS.Namespace = s.Namespace
-					embeddedS.Name = s.Namespace + "." + embeddedS.Type
+// embeddedStruct is embedded struct which fields are promoted to parent struct.
+type embeddedStruct struct {
+	*Struct
+	index []int
+}
+
+// properties returns struct properties visible in JSON.
+// Fields of embedded structs are promoted by the same rules as in encoding/json: breadth first,
+// shallower fields shadow deeper ones and tagged fields win at the same depth, other conflicting fields are omitted.
+func (s *Struct) properties(pi *PackageInfo) ([]Property, error) {
+	var fields []jsonField
+	current, next := []embeddedStruct{}, []embeddedStruct{{Struct: s}}
+	count, nextCount := map[*types.Struct]int{}, map[*types.Struct]int{}
+	visited := map[*types.Struct]bool{}
+
+	for len(next) > 0 {
+		current, next = next, current[:0]
+		count, nextCount = nextCount, map[*types.Struct]int{}
+
+		for _, es := range current {
+			if visited[es.typ] {
+				continue
+			}
+
+			visited[es.typ] = true
+			pi.index(es.pkg)
+			for i := 0; i < es.typ.NumFields(); i++ {
+				v, tag := es.typ.Field(i), es.typ.Tag(i)
+				ft := types.Unalias(v.Type())
+				if p, ok := ft.(*types.Pointer); ok {
+					ft = types.Unalias(p.Elem())
 				}
 
-				if currentS, ok := pi.Structs[embeddedS.Name]; !ok || (currentS.StructType == nil && embeddedS.StructType != nil) {
-					pi.Structs[embeddedS.Name] = embeddedS
+				_, isStruct := ft.Underlying().(*types.Struct)
+				if v.Embedded() {
+					// exported fields of unexported embedded structs are still promoted
+					if !v.Exported() && !isStruct {
+						continue
+					}
+				} else if !v.Exported() {
+					continue
 				}
 
-				if err := embeddedS.parse(pi); err != nil {
-					return err
+				// do not parse fields that ignored in json
+				if reflect.StructTag(tag).Get("json") == "-" {
+					continue
 				}
 
-				if embeddedS.Properties != nil && len(embeddedS.Properties) > 0 {
-					s.Properties = append(s.Properties, embeddedS.Properties...)
+				name, omitEmpty, quoted := parseJsonTag(tag)
+				if !isValidJsonName(name) {
+					name = ""
 				}
-			}
 
-			continue
-		}
+				index := make([]int, len(es.index)+1)
+				copy(index, es.index)
+				index[len(es.index)] = i
 
-		smdType, itemType := parseSMDType(field.Type)
+				named, isNamed := ft.(*types.Named)
+				if name != "" || !v.Embedded() || !isNamed || !isStruct {
+					p, err := es.property(pi, i, omitEmpty, quoted)
+					if err != nil {
+						return nil, err
+					}
 
-		var ref string
-		// parse field with struct type
-		if internalS := parseStruct(field.Type); internalS != nil {
-			// set right namespace for struct from another package
-			if internalS.Namespace == "." && s.Namespace != "." {
-				internalS.Namespace = s.Namespace
-				internalS.Name = s.Namespace + "." + internalS.Type
-			}
+					f := jsonField{Property: p, tagged: name != "", index: index}
+					if f.tagged {
+						f.Name = name
+					}
 
-			ref = internalS.Name
-			if currentS, ok := pi.Structs[internalS.Name]; !ok || (currentS.StructType == nil && internalS.StructType != nil) {
-				pi.Structs[internalS.Name] = internalS
-			}
+					fields = append(fields, f)
+					if count[es.typ] > 1 {
+						// struct embedded several times at the same depth, duplicate field to annihilate it
+						fields = append(fields, f)
+					}
 
-			// avoid self-linked infinite recursion
-			if internalS.Name != s.Name {
-				if err := internalS.parse(pi); err != nil {
-					return err
+					continue
+				}
+
+				// promote fields of embedded struct on next depth
+				embedded := pi.Structs[pi.addStruct(named)]
+				nextCount[embedded.typ]++
+				if nextCount[embedded.typ] == 1 {
+					next = append(next, embeddedStruct{Struct: embedded, index: index})
 				}
 			}
 		}
+	}
 
-		// parse inline struct
-		if inlineStructType, ok := field.Type.(*ast.StructType); ok {
-			// call struct by first property name
-			inlineS := &Struct{
-				Name:       s.Name + "_" + field.Names[0].Name,
-				Namespace:  s.Namespace,
-				Type:       s.Type + "_" + field.Names[0].Name,
-				StructType: inlineStructType,
-			}
+	return dominantFields(fields), nil
+}
+
+// dominantFields returns fields which are not shadowed by other fields with the same name, sorted by index sequence.
+func dominantFields(fields []jsonField) []Property {
+	sort.SliceStable(fields, func(i, j int) bool {
+		x := fields
+		if x[i].Name != x[j].Name {
+			return x[i].Name < x[j].Name
+		}
+		if len(x[i].index) != len(x[j].index) {
+			return len(x[i].index) < len(x[j].index)
+		}
+		if x[i].tagged != x[j].tagged {
+			return x[i].tagged
+		}
+		return lessIndex(x[i].index, x[j].index)
+	})
+
+	var dominant []jsonField
+	for i := 0; i < len(fields); {
+		j := i + 1
+		for j < len(fields) && fields[j].Name == fields[i].Name {
+			j++
+		}
+
+		// fields at the same depth with the same tagging conflict
+		if j-i == 1 || len(fields[i].index) < len(fields[i+1].index) || fields[i].tagged != fields[i+1].tagged {
+			dominant = append(dominant, fields[i])
+		}
+
+		i = j
+	}
+
+	sort.Slice(dominant, func(i, j int) bool {
+		return lessIndex(dominant[i].index, dominant[j].index)
+	})
+
+	properties := make([]Property, 0, len(dominant))
+	for _, f := range dominant {
+		properties = append(properties, f.Property)
+	}
+
+	return properties
+}
+
+// lessIndex compares field index sequences.
+func lessIndex(x, y []int) bool {
+	for k, xik := range x {
+		if k >= len(y) {
+			return false
+		}
+		if xik != y[k] {
+			return xik < y[k]
+		}
+	}
+
+	return len(x) < len(y)
+}
 
+// property returns property of i-th struct field named by Go field name.
+func (s *Struct) property(pi *PackageInfo, i int, omitEmpty, quoted bool) (Property, error) {
+	v, field := s.typ.Field(i), pi.fields[s.typ.Field(i).Pos()]
+	constraints, err := parseConstraintsTag(s.typ.Tag(i))
+	if err != nil {
+		return Property{}, fmt.Errorf("%s.%s: %s", s.Name, v.Name(), err)
+	}
+
+	var smdType SMDType
+	if inlineStructType, ok := v.Type().(*types.Struct); ok {
+		// parse inline struct, call struct by first name of field
+		firstName := v.Name()
+		if field != nil && len(field.Names) > 0 {
+			firstName = field.Names[0].Name
+		}
+
+		inlineS := &Struct{
+			Name:      s.Name + "_" + firstName,
+			Namespace: s.Namespace,
+			Type:      s.Type + "_" + firstName,
+			typ:       inlineStructType,
+			pkg:       s.pkg,
+		}
+
+		if _, ok := pi.Structs[inlineS.Name]; !ok {
 			pi.Structs[inlineS.Name] = inlineS
-			ref = inlineS.Name
-			if err := inlineS.parse(pi); err != nil {
-				return err
-			}
 		}
 
-		// description
-		description := parseCommentGroup(field.Doc)
+		smdType = SMDType{Type: "Object", Ref: inlineS.Name}
+	} else {
+		smdType = pi.smdType(v.Type())
+	}
+
+	smdType.Constraints = constraints
+	if err := smdType.validateEnum(); err != nil {
+		return Property{}, fmt.Errorf("%s.%s: %s", s.Name, v.Name(), err)
+	}
+
+	// values are encoded in JSON strings with string option, enum values are converted too
+	if quoted && isQuotable(v.Type()) {
+		smdType = smdType.quoted()
+	}
+
+	// description
+	var description string
+	if field != nil {
+		description = parseCommentGroup(field.Doc)
 		comment := parseCommentGroup(field.Comment)
 		if description != "" && comment != "" {
 			description += "\n"
 		}
 		description += comment
+		if p, ok := pi.packages[s.pkg]; description == "" && ok {
+			description = pi.typeDescription(p.TypesInfo, field.Type)
+		}
+	}
 
-		// parse names
-		for i, name := range field.Names {
-			if !ast.IsExported(name.Name) {
-				continue
-			}
+	_, isPointer := types.Unalias(v.Type()).(*types.Pointer)
+	return Property{
+		Name:        v.Name(),
+		Description: description,
+		Optional:    isPointer || omitEmpty,
+		Nullable:    isPointer,
+		SMDType:     smdType,
+	}, nil
+}
 
-			p := Property{
-				Name:        name.Name,
-				Description: description,
-				SMDType: SMDType{
-					Type:      smdType,
-					ItemsType: itemType,
-					Ref:       ref,
-				},
-			}
+// isQuotable checks that string option of json tag is applicable to type: booleans, numbers, strings and pointers to them.
+func isQuotable(t types.Type) bool {
+	t = types.Unalias(t)
+	if p, ok := t.(*types.Pointer); ok {
+		t = p.Elem()
+	}
 
-			if i == 0 {
-				// tag only for first name
-				if tag == "-" {
-					continue
-				} else if tag != "" {
-					p.Name = tag
-				}
-			}
+	b, ok := t.Underlying().(*types.Basic)
+	return ok && b.Info()&(types.IsBoolean|types.IsNumeric|types.IsString) != 0 && b.Info()&types.IsComplex == 0
+}
 
-			s.Properties = append(s.Properties, p)
+// parseJsonTag returns field name, omitempty and string options from json tag.
+func parseJsonTag(tag string) (name string, omitEmpty, quoted bool) {
+	opts := strings.Split(reflect.StructTag(tag).Get("json"), ",")
+	for _, opt := range opts[1:] {
+		switch opt {
+		case "omitempty":
+			omitEmpty = true
+		case "string":
+			quoted = true
 		}
 	}
 
-	return nil
+	return opts[0], omitEmpty, quoted
 }
 
-func parseJsonTag(bl *ast.BasicLit) string {
-	if bl == nil {
-		return ""
+// isValidJsonName checks that name from json tag is valid, otherwise field name is used like in encoding/json.
+func isValidJsonName(name string) bool {
+	if name == "" {
+		return false
 	}
 
-	tags := bl.Value[1 : len(bl.Value)-1] // remove quotes ``
-	tag := strings.Split(reflect.StructTag(tags).Get("json"), ",")[0]
+	for _, c := range name {
+		switch {
+		case strings.ContainsRune("!#$%&()*+-./:;<=>?@[]^_{|}~ ", c):
+			// backslash and quote chars are reserved
+		case !unicode.IsLetter(c) && !unicode.IsDigit(c):
+			return false
+		}
+	}
 
-	return tag
+	return true
 }
 
-// Definitions returns list of structs used inside smdType
-func Definitions(smdType SMDType, structs map[string]*Struct) []*Struct {
-	if smdType.Ref == "" {
-		return nil
+// parseConstraintsTag returns constraints from zenrpc tag, e.g. `zenrpc:"min=1 max=100"`.
+func parseConstraintsTag(tag string) (Constraints, error) {
+	c, rest, err := parseConstraints(reflect.StructTag(tag).Get(zenrpcTag))
+	if err == nil && rest != "" {
+		err = fmt.Errorf("invalid constraint %q", rest)
 	}
 
-	names := definitions(smdType, structs)
-	// todo what about arrays?
-	if smdType.Type == "Array" /*|| smdType.Type == "Object" */ {
-		// add object to definitions if type array
-		names = append([]string{smdType.Ref}, names...)
-	}
+	return c, err
+}
 
-	result := []*Struct{}
-	unique := map[string]struct{}{} // structs in result must be unique
-	for _, name := range names {
-		if s, ok := structs[name]; ok {
-			if _, ok := unique[name]; !ok {
-				result = append(result, s)
-				unique[name] = struct{}{}
-			}
+// Required returns names of struct properties which are always present in JSON.
+func (s Struct) Required() []string {
+	var result []string
+	for _, p := range s.Properties {
+		if !p.Optional {
+			result = append(result, p.Name)
 		}
 	}
 
 	return result
 }
 
-// definitions returns list of struct names used inside smdType
-func definitions(smdType SMDType, structs map[string]*Struct) []string {
-	result := []string{}
-	if s, ok := structs[smdType.Ref]; ok {
-		for _, p := range s.Properties {
-			if p.SMDType.Ref != "" {
-				result = append(result, p.SMDType.Ref)
+// Definitions returns list of structs used inside service methods, including nested ones.
+func Definitions(s *Service, structs map[string]*Struct) []*Struct {
+	var types []*SMDType
+	for _, m := range s.Methods {
+		types = append(types, m.smdTypes()...)
+	}
 
-				// avoid self-linked infinite recursion
-				if smdType.Ref != p.SMDType.Ref {
-					result = append(result, definitions(p.SMDType, structs)...)
-				}
-			}
+	return usedStructs(types, structs)
+}
+
+// HasConstraints checks that method arguments or structs used in them have constraints.
+func HasConstraints(m *Method, structs map[string]*Struct) bool {
+	var args []*SMDType
+	for i := range m.Args {
+		args = append(args, &m.Args[i].SMDType)
+	}
+
+	types := args
+	for _, s := range usedStructs(args, structs) {
+		for i := range s.Properties {
+			types = append(types, &s.Properties[i].SMDType)
+		}
+	}
+
+	for _, t := range types {
+		if t.hasConstraints() {
+			return true
 		}
 	}
 
+	return false
+}
+
+// hasConstraints checks that type, its items or map values have constraints.
+func (t *SMDType) hasConstraints() bool {
+	if t == nil {
+		return false
+	}
+
+	return !t.Constraints.IsEmpty() || t.Items.hasConstraints() || t.Values.hasConstraints()
+}
+
+// smdTypes returns types of method arguments and return value.
+func (m *Method) smdTypes() []*SMDType {
+	var result []*SMDType
+	for i := range m.Args {
+		result = append(result, &m.Args[i].SMDType)
+	}
+
+	if m.SMDReturn != nil {
+		result = append(result, &m.SMDReturn.SMDType)
+	}
+
 	return result
 }
 
-func uniqueStructsNamespaces(structs map[string]*Struct) (set map[string]struct{}) {
-	set = make(map[string]struct{})
-	for _, s := range structs {
-		if s.Namespace != "" {
-			if _, ok := set[s.Namespace]; !ok {
-				set[s.Namespace] = struct{}{}
-			}
+// usedStructs returns list of structs used inside types, including nested ones.
+func usedStructs(types []*SMDType, structs map[string]*Struct) []*Struct {
+	result := []*Struct{}
+	unique := map[string]struct{}{} // structs in result must be unique
+
+	var add func(smdType *SMDType)
+	add = func(smdType *SMDType) {
+		if smdType == nil {
+			return
+		}
+
+		add(smdType.Items)
+		add(smdType.Values)
+		for i := range smdType.OneOf {
+			add(&smdType.OneOf[i])
+		}
+
+		st, ok := structs[smdType.Ref]
+		if _, done := unique[smdType.Ref]; !ok || done {
+			return
+		}
+
+		// mark struct before properties to avoid self-linked infinite recursion
+		unique[smdType.Ref] = struct{}{}
+		result = append(result, st)
+		for i := range st.Properties {
+			add(&st.Properties[i].SMDType)
 		}
 	}
 
-	return
+	for _, t := range types {
+		add(t)
+	}
+
+	return result
 }
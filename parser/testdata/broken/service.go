@@ -0,0 +1,15 @@
+package broken
+
+import "github.com/semrush/zenrpc/v2"
+
+// declarations of generated code are not reported
+var (
+	_ zenrpc.Invoker = BrokenService{}
+	_                = BrokenService{}.SMD
+	_                = RPC.BrokenService.Get
+	_                = NewBrokenServiceClient
+)
+
+type BrokenService struct{ zenrpc.Service }
+
+func (BrokenService) Get(u Undefinedd) int { return 0 }
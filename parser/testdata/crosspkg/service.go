@@ -0,0 +1,13 @@
+package crosspkg
+
+import "github.com/semrush/zenrpc/v2/testdata/subservice"
+
+// ArithService embeds service from another package.
+type ArithService struct {
+	subarithservice.SubArithService
+} //zenrpc
+
+// Ping returns pong.
+func (ArithService) Ping() string {
+	return "pong"
+}
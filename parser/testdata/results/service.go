@@ -0,0 +1,8 @@
+package results
+
+import "github.com/semrush/zenrpc/v2"
+
+type ResultsService struct{ zenrpc.Service }
+
+// Get returns values, a and A are both A in result struct.
+func (ResultsService) Get() (a int, A string) { return 0, "" }
@@ -0,0 +1,337 @@
+package parser
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+	"unicode"
+)
+
+const zenrpcPackagePath = "github.com/semrush/zenrpc/v2"
+
+// index collects struct fields, doc comments and enum values of named types declared in package.
+// Packages are indexed once on demand, because dependencies are not needed until their types are used.
+func (pi *PackageInfo) index(pkg *types.Package) {
+	if pkg == nil || pi.indexed[pkg] {
+		return
+	}
+	pi.indexed[pkg] = true
+
+	for tn, values := range enumValues(pkg) {
+		pi.enums[tn] = values
+	}
+
+	p, ok := pi.packages[pkg]
+	if !ok {
+		return
+	}
+
+	for _, f := range p.Syntax {
+		ast.Inspect(f, func(n ast.Node) bool {
+			switch v := n.(type) {
+			case *ast.GenDecl:
+				if v.Tok != token.TYPE {
+					return true
+				}
+
+				for _, spec := range v.Specs {
+					ts := spec.(*ast.TypeSpec)
+					tn, ok := p.TypesInfo.Defs[ts.Name].(*types.TypeName)
+					if !ok {
+						continue
+					}
+
+					doc := ts.Doc
+					if doc == nil && len(v.Specs) == 1 {
+						doc = v.Doc
+					}
+
+					pi.typeDocs[tn] = doc
+					if values, ok := parseEnumComment(doc); ok && len(values) > 0 {
+						if err := validateEnumComment(tn, values); err != nil && pi.err == nil {
+							pi.err = fmt.Errorf("%s.%s: %s", pkg.Name(), tn.Name(), err)
+						}
+						pi.enums[tn] = values
+					}
+
+					t, ok, err := parseTypeComment(doc)
+					if err != nil && pi.err == nil {
+						pi.err = fmt.Errorf("%s.%s: %s", pkg.Name(), tn.Name(), err)
+					} else if ok {
+						pi.typeComments[tn] = t
+					}
+				}
+			case *ast.StructType:
+				for _, field := range v.Fields.List {
+					for _, name := range field.Names {
+						pi.fields[name.Pos()] = field
+					}
+
+					// position of embedded field is position of type name
+					if len(field.Names) == 0 {
+						ast.Inspect(field.Type, func(n ast.Node) bool {
+							if ident, ok := n.(*ast.Ident); ok {
+								pi.fields[ident.Pos()] = field
+							}
+							return true
+						})
+					}
+				}
+			}
+
+			return true
+		})
+	}
+}
+
+// namespace returns prefix for type names from pkg as in SMDType.Ref, e.g. model.
+// Types of current package are prefixed too, because definitions of services from different packages are merged.
+func (pi *PackageInfo) namespace(pkg *types.Package) string {
+	if pkg == nil {
+		return ""
+	}
+
+	return pkg.Name() + "."
+}
+
+// smdType returns SMD type representation of t. Named non-struct types are resolved to their underlying types,
+// structs are added to PackageInfo.Structs and referenced by name.
+func (pi *PackageInfo) smdType(t types.Type) SMDType {
+	switch v := types.Unalias(t).(type) {
+	case *types.Pointer:
+		return pi.smdType(v.Elem())
+	case *types.Named:
+		if t, ok := pi.mappedType(v); ok {
+			return t
+		}
+
+		if _, ok := v.Underlying().(*types.Struct); ok {
+			return SMDType{Type: "Object", Ref: pi.addStruct(v)}
+		}
+
+		return pi.namedSMDType(v)
+	case *types.Slice:
+		// []byte is encoded as base64 string
+		if b, ok := types.Unalias(v.Elem()).(*types.Basic); ok && b.Kind() == types.Byte {
+			return SMDType{Type: "String", ContentEncoding: "base64"}
+		}
+
+		items := pi.smdType(v.Elem())
+		return SMDType{Type: "Array", Items: &items}
+	case *types.Array:
+		items := pi.smdType(v.Elem())
+		return SMDType{Type: "Array", Items: &items}
+	case *types.Map:
+		values := pi.smdType(v.Elem())
+		return SMDType{Type: "Object", Values: &values}
+	case *types.Interface:
+		return SMDType{Type: "Any"} // any value could be encoded
+	case *types.Basic:
+		switch {
+		case v.Info()&types.IsBoolean != 0:
+			return SMDType{Type: "Boolean"}
+		case v.Info()&types.IsString != 0:
+			return SMDType{Type: "String"}
+		case v.Info()&types.IsInteger != 0:
+			return SMDType{Type: "Integer"}
+		case v.Info()&types.IsFloat != 0, v.Info()&types.IsComplex != 0:
+			return SMDType{Type: "Float"}
+		}
+	}
+
+	return SMDType{Type: "Object"} // default complex type is object
+}
+
+// oneOfType returns SMD type of value which is one of listed types assignable to t.
+// Type names are resolved in file scope of pos, e.g. Circle or model.Point.
+func (pi *PackageInfo) oneOfType(names []string, pos token.Pos, t types.Type) (SMDType, error) {
+	result := SMDType{Type: "Any"}
+	for _, name := range names {
+		tv, err := types.Eval(pi.pkg.Fset, pi.pkg.Types, pos, name)
+		if err != nil {
+			return SMDType{}, err
+		}
+
+		if !tv.IsType() {
+			return SMDType{}, fmt.Errorf("%s is not a type", name)
+		}
+
+		if !types.AssignableTo(tv.Type, t) && !types.AssignableTo(types.NewPointer(tv.Type), t) {
+			return SMDType{}, fmt.Errorf("%s is not assignable to %s", name, types.TypeString(t, types.RelativeTo(pi.pkg.Types)))
+		}
+
+		result.OneOf = append(result.OneOf, pi.smdType(tv.Type))
+	}
+
+	return result, nil
+}
+
+// namedSMDType returns SMD type of named non-struct type with enum values of its constants.
+func (pi *PackageInfo) namedSMDType(named *types.Named) SMDType {
+	tn := named.Obj()
+	if pi.resolving[tn] {
+		return SMDType{Type: "Object"} // recursive type, e.g. type Tree map[string]Tree
+	}
+
+	pi.resolving[tn] = true
+	defer delete(pi.resolving, tn)
+
+	t := pi.smdType(named.Underlying())
+	if t.isBasic() {
+		pi.index(tn.Pkg())
+		t.Enum = pi.enums[tn]
+	}
+
+	return t
+}
+
+// addStruct adds named struct to PackageInfo.Structs if it is not there and returns its name.
+func (pi *PackageInfo) addStruct(named *types.Named) string {
+	name := pi.namespace(named.Obj().Pkg()) + typeName(named)
+	if _, ok := pi.Structs[name]; !ok {
+		pi.Structs[name] = &Struct{
+			Name:      name,
+			Namespace: named.Obj().Pkg().Name(),
+			Type:      typeName(named),
+			typ:       named.Underlying().(*types.Struct),
+			pkg:       named.Obj().Pkg(),
+		}
+	} else if s := pi.Structs[name]; s.pkg.Path() != named.Obj().Pkg().Path() || !types.Identical(s.typ, named.Underlying()) {
+		// definitions are shared by name, e.g. model.Point from different packages named model
+		if pi.err == nil {
+			pi.err = fmt.Errorf("definition %s: type %s conflicts with %s.%s", name, named, s.pkg.Path(), s.Type)
+		}
+	}
+
+	return name
+}
+
+// typeName returns name of named type, type arguments of generic type are appended with underscore, e.g. Page_Person.
+func typeName(named *types.Named) string {
+	name := named.Obj().Name()
+	args := named.TypeArgs()
+	for i := 0; i < args.Len(); i++ {
+		arg := types.TypeString(args.At(i), func(*types.Package) string { return "" })
+		name += "_" + strings.Map(func(r rune) rune {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				return r
+			}
+			return -1
+		}, arg)
+	}
+
+	return name
+}
+
+// typeDescription returns doc comment of named non-struct type or alias used in expr.
+func (pi *PackageInfo) typeDescription(info *types.Info, expr ast.Expr) string {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		return pi.typeDescription(info, star.X)
+	}
+
+	var ident *ast.Ident
+	switch v := expr.(type) {
+	case *ast.Ident:
+		ident = v
+	case *ast.SelectorExpr:
+		ident = v.Sel
+	default:
+		return ""
+	}
+
+	tn, ok := info.Uses[ident].(*types.TypeName)
+	if !ok || tn.Pkg() == nil {
+		return ""
+	}
+
+	if !tn.IsAlias() {
+		switch tn.Type().Underlying().(type) {
+		case *types.Struct, *types.Interface:
+			return "" // structs are described in definitions
+		}
+	}
+
+	pi.index(tn.Pkg())
+	return parseCommentGroup(pi.typeDocs[tn])
+}
+
+// derefType returns element type of pointer or t itself.
+func derefType(t types.Type) types.Type {
+	if p, ok := types.Unalias(t).(*types.Pointer); ok {
+		return p.Elem()
+	}
+
+	return t
+}
+
+// isInlineStruct checks that t is anonymous struct or pointer to it.
+func isInlineStruct(t types.Type) bool {
+	_, ok := types.Unalias(derefType(t)).(*types.Struct)
+	return ok
+}
+
+// unsupportedType checks that values of t can't be encoded in JSON, e.g. channels and functions.
+func unsupportedType(t types.Type) bool {
+	return unsupported(t, make(map[types.Type]bool))
+}
+
+func unsupported(t types.Type, visited map[types.Type]bool) bool {
+	t = types.Unalias(t)
+	if visited[t] {
+		return false // recursive type, e.g. type Tree map[string]Tree
+	}
+	visited[t] = true
+
+	if named, ok := t.(*types.Named); ok && (implements(named, jsonMarshaler) || implements(named, textMarshaler)) {
+		return false
+	}
+
+	switch v := t.Underlying().(type) {
+	case *types.Chan, *types.Signature:
+		return true
+	case *types.Basic:
+		return v.Info()&types.IsComplex != 0 || v.Kind() == types.UnsafePointer
+	case *types.Pointer:
+		return unsupported(v.Elem(), visited)
+	case *types.Slice:
+		return unsupported(v.Elem(), visited)
+	case *types.Array:
+		return unsupported(v.Elem(), visited)
+	case *types.Map:
+		return unsupported(v.Elem(), visited)
+	}
+
+	return false
+}
+
+// isBasic checks that type is primitive JSON type.
+func (t SMDType) isBasic() bool {
+	switch t.Type {
+	case "Boolean", "String", "Integer", "Float":
+		return true
+	default:
+		return false
+	}
+}
+
+// isNamed checks that t or pointer to t is named type with given package path and name.
+func isNamed(t types.Type, path, name string) bool {
+	if p, ok := types.Unalias(t).(*types.Pointer); ok {
+		t = p.Elem()
+	}
+
+	named, ok := types.Unalias(t).(*types.Named)
+	return ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == path && named.Obj().Name() == name
+}
+
+// isContext checks that t is context.Context.
+func isContext(t types.Type) bool {
+	return isNamed(t, "context", "Context")
+}
+
+// isError checks that t is error or zenrpc.Error.
+func isError(t types.Type) bool {
+	return t != nil && types.Identical(t, types.Universe.Lookup("error").Type()) || isNamed(t, zenrpcPackagePath, "Error")
+}
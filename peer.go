@@ -0,0 +1,300 @@
+package zenrpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// context key for peer.
+const peerKey contextKey = "peer"
+
+// ErrPeerClosed is returned for requests sent to closed peer.
+var ErrPeerClosed = errors.New("peer connection closed")
+
+// Handler processes JSON-RPC 2.0 message and returns response. Server implements it.
+type Handler interface {
+	Do(ctx context.Context, message []byte) ([]byte, error)
+}
+
+// Peer is a side of bidirectional JSON-RPC 2.0 WebSocket connection.
+// It sends requests concurrently and matches responses by ID. Incoming requests are processed by handler.
+// Peer implements client.Transport, so it could be used for calling remote methods with client package.
+type Peer struct {
+	conn    *websocket.Conn
+	handler Handler
+
+	writeMu sync.Mutex
+
+	mu      sync.Mutex
+	pending map[string]chan []byte
+	closed  bool
+	err     error // failure of request processing, connection is closed after it
+}
+
+// peerMessage is a minimal structure for detecting requests and responses, it holds present members of message.
+type peerMessage map[string]json.RawMessage
+
+// isResponse checks that message has id and either result or error, but not method.
+func (m peerMessage) isResponse() bool {
+	_, hasID := m["id"]
+	_, hasMethod := m["method"]
+	_, hasResult := m["result"]
+	_, hasError := m["error"]
+
+	return hasID && !hasMethod && (hasResult || hasError)
+}
+
+// NewPeer returns new peer for WebSocket connection. Incoming requests are ignored if handler is nil.
+func NewPeer(conn *websocket.Conn, handler Handler) *Peer {
+	return &Peer{
+		conn:    conn,
+		handler: handler,
+		pending: make(map[string]chan []byte),
+	}
+}
+
+// Serve reads messages from connection until it closed. Incoming requests are processed in separate goroutines
+// with given context and peer in it. Any other messages than responses are passed to handler,
+// so malformed messages get error response. If handler or write of response fails, connection is closed
+// and the failure is returned.
+func (p *Peer) Serve(ctx context.Context) error {
+	defer p.closePending()
+
+	ctx = newPeerContext(ctx, p)
+	for {
+		mt, message, err := p.conn.ReadMessage()
+		if ferr := p.failure(); ferr != nil {
+			return ferr
+		}
+
+		// normal closure
+		if websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+			return nil
+		}
+		// abnormal closure
+		if err != nil {
+			return fmt.Errorf("read message failed with err=%w", err)
+		}
+
+		ids, isResponse := parsePeerMessage(message)
+		if isResponse {
+			p.resolve(ids, message)
+			continue
+		}
+
+		if p.handler == nil {
+			continue
+		}
+
+		go func(mt int, message []byte) {
+			data, err := p.handler.Do(ctx, message)
+			if err != nil {
+				p.fail(fmt.Errorf("marshal json response failed with err=%w", err))
+				return
+			}
+
+			if isNullMessage(data) {
+				return
+			}
+
+			if err := p.write(mt, data); err != nil {
+				p.fail(fmt.Errorf("write response failed with err=%w", err))
+			}
+		}(mt, message)
+	}
+}
+
+// Send writes message to connection and waits for response. It returns immediately if message contains only notifications.
+func (p *Peer) Send(ctx context.Context, message []byte) ([]byte, error) {
+	ids, _ := parsePeerMessage(message)
+	if len(ids) == 0 {
+		return nil, p.write(websocket.TextMessage, message)
+	}
+
+	// register pending request by all ids from message
+	ch := make(chan []byte, 1)
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil, ErrPeerClosed
+	}
+
+	for _, id := range ids {
+		p.pending[id] = ch
+	}
+	p.mu.Unlock()
+
+	if err := p.write(websocket.TextMessage, message); err != nil {
+		p.remove(ids)
+		return nil, err
+	}
+
+	select {
+	case data, ok := <-ch:
+		if !ok {
+			return nil, ErrPeerClosed
+		}
+
+		return data, nil
+	case <-ctx.Done():
+		p.remove(ids)
+		return nil, ctx.Err()
+	}
+}
+
+// Close sends close message and closes connection.
+func (p *Peer) Close() error {
+	p.writeMu.Lock()
+	p.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+	p.writeMu.Unlock()
+
+	return p.conn.Close()
+}
+
+// write writes message to connection. Gorilla WebSocket supports only one concurrent writer.
+func (p *Peer) write(mt int, message []byte) error {
+	p.writeMu.Lock()
+	defer p.writeMu.Unlock()
+
+	return p.conn.WriteMessage(mt, message)
+}
+
+// resolve sends response to pending request with one of given ids.
+// Responses without id (e.g. parse error or rejected batch) can't be matched, they are sent to all pending requests,
+// otherwise the request which caused it would wait forever.
+func (p *Peer) resolve(ids []string, message []byte) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(ids) == 0 {
+		p.resolveAll(message)
+		return
+	}
+
+	var ch chan []byte
+	for _, id := range ids {
+		if ch = p.pending[id]; ch != nil {
+			break
+		}
+	}
+
+	if ch == nil {
+		return
+	}
+
+	// remove all ids of resolved request
+	for id, c := range p.pending {
+		if c == ch {
+			delete(p.pending, id)
+		}
+	}
+
+	ch <- message
+	close(ch)
+}
+
+// fail closes connection with internal error status. Serve returns the first failure.
+func (p *Peer) fail(err error) {
+	p.mu.Lock()
+	if p.err == nil {
+		p.err = err
+	}
+	p.mu.Unlock()
+
+	p.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseInternalServerErr, ""), time.Time{})
+	p.conn.Close()
+}
+
+// failure returns failure of request processing.
+func (p *Peer) failure() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.err
+}
+
+// remove removes pending request by ids.
+func (p *Peer) remove(ids []string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, id := range ids {
+		delete(p.pending, id)
+	}
+}
+
+// closePending closes all pending requests.
+func (p *Peer) closePending() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.closed = true
+	p.resolveAll(nil)
+}
+
+// resolveAll sends message to all pending requests and removes them, requests are closed without response for nil message.
+// Caller must hold p.mu.
+func (p *Peer) resolveAll(message []byte) {
+	closed := make(map[chan []byte]struct{})
+	for id, ch := range p.pending {
+		delete(p.pending, id)
+		if _, ok := closed[ch]; !ok {
+			closed[ch] = struct{}{}
+			if message != nil {
+				ch <- message
+			}
+			close(ch)
+		}
+	}
+}
+
+// parsePeerMessage returns ids from single or batch message and checks if it is well-formed response.
+// Responses with null id, e.g. parse errors, are responses too, but they can't be matched with requests by id.
+func parsePeerMessage(message []byte) (ids []string, isResponse bool) {
+	var messages []peerMessage
+	if IsArray(message) {
+		if err := json.Unmarshal(message, &messages); err != nil {
+			return nil, false
+		}
+	} else {
+		var m peerMessage
+		if err := json.Unmarshal(message, &m); err != nil {
+			return nil, false
+		}
+		messages = []peerMessage{m}
+	}
+
+	isResponse = len(messages) > 0
+	for _, m := range messages {
+		isResponse = isResponse && m.isResponse()
+		if id, ok := m["id"]; ok && !isNullMessage(id) {
+			ids = append(ids, string(id))
+		}
+	}
+
+	return ids, isResponse
+}
+
+// isNullMessage checks if message is empty or null.
+func isNullMessage(message []byte) bool {
+	message = bytes.TrimSpace(message)
+	return len(message) == 0 || bytes.Equal(message, []byte("null"))
+}
+
+// newPeerContext creates new context with peer.
+func newPeerContext(ctx context.Context, p *Peer) context.Context {
+	return context.WithValue(ctx, peerKey, p)
+}
+
+// PeerFromContext returns peer from context. It's set for requests received via ServeWS or other Peer.
+func PeerFromContext(ctx context.Context) (*Peer, bool) {
+	p, ok := ctx.Value(peerKey).(*Peer)
+	return p, ok
+}
@@ -11,6 +11,7 @@ import (
 	"unicode"
 
 	"github.com/gorilla/websocket"
+	"github.com/semrush/zenrpc/v2/openrpc"
 	"github.com/semrush/zenrpc/v2/smd"
 )
 
@@ -23,6 +24,12 @@ const (
 	// defaultTargetURL is default value for SMD target url.
 	defaultTargetURL = "/"
 
+	// defaultAPITitle is default title for OpenRPC document.
+	defaultAPITitle = "zenrpc"
+
+	// defaultAPIVersion is default API version for OpenRPC document.
+	defaultAPIVersion = "1.0.0"
+
 	// context key for http.Request object.
 	requestKey contextKey = "request"
 
@@ -32,6 +39,18 @@ const (
 	// context key for ID.
 	IDKey contextKey = "id"
 
+	// context key for transport name.
+	transportKey contextKey = "transport"
+
+	// TransportHTTP is transport name for requests received via ServeHTTP.
+	TransportHTTP = "http"
+
+	// TransportWS is transport name for requests received via ServeWS.
+	TransportWS = "ws"
+
+	// TransportTCP is transport name for requests received via ServeTCP.
+	TransportTCP = "tcp"
+
 	// contentTypeJSON is default content type for HTTP transport.
 	contentTypeJSON = "application/json"
 )
@@ -42,6 +61,18 @@ type MiddlewareFunc func(InvokeFunc) InvokeFunc
 // InvokeFunc is a function for processing single JSON-RPC 2.0 Request after validation and parsing.
 type InvokeFunc func(context.Context, string, json.RawMessage) Response
 
+// MessageHookFunc is a function for executing on raw JSON-RPC 2.0 message before parsing.
+// It could replace context or message. Returned error rejects whole message.
+type MessageHookFunc func(ctx context.Context, message json.RawMessage) (context.Context, json.RawMessage, error)
+
+// RequestsHookFunc is a function for executing on all parsed JSON-RPC 2.0 Requests before processing.
+// It could replace context or requests. Returned error rejects whole batch.
+type RequestsHookFunc func(ctx context.Context, requests []Request) (context.Context, []Request, error)
+
+// ResponsesHookFunc is a function for executing on collected JSON-RPC 2.0 Responses.
+// Notifications have no responses, so responses could be shorter than requests.
+type ResponsesHookFunc func(ctx context.Context, requests []Request, responses []Response) []Response
+
 // Invoker implements service handler.
 type Invoker interface {
 	Invoke(ctx context.Context, method string, params json.RawMessage) Response
@@ -59,9 +90,13 @@ type Options struct {
 	// TargetURL is RPC endpoint.
 	TargetURL string
 
-	// ExposeSMD exposes SMD schema with ?smd GET parameter.
+	// ExposeSMD exposes SMD schema with ?smd GET parameter and OpenRPC document with ?smd=openrpc GET parameter
+	// and rpc.discover method.
 	ExposeSMD bool
 
+	// OpenRPCInfo sets info object for OpenRPC document. Default title is zenrpc, default version is 1.0.0.
+	OpenRPCInfo openrpc.Info
+
 	// DisableTransportChecks disables Content-Type and methods checks. Use only for development mode.
 	DisableTransportChecks bool
 
@@ -73,14 +108,27 @@ type Options struct {
 
 	// HideErrorDataField removes data field from response error
 	HideErrorDataField bool
+
+	// ValidateParams enables validation of request params against SMD schema of method before invoke.
+	// Invalid params are rejected with InvalidParams error, data field contains list of smd.FieldError.
+	ValidateParams bool
+
+	// BeginTx enables transactional batches. Batch requests run sequentially in transaction returned by BeginTx.
+	// Transaction is committed if all requests succeeded, otherwise it's rolled back and all responses are failed.
+	BeginTx TxBeginFunc
 }
 
 // Server is JSON-RPC 2.0 Server.
 type Server struct {
-	services   map[string]Invoker
-	options    Options
-	middleware []MiddlewareFunc
-	logger     Printer
+	services       map[string]Invoker
+	schemas        map[string]smd.Service // method schemas for params validation
+	definitions    map[string]smd.Definition
+	options        Options
+	middleware     []MiddlewareFunc
+	messageHooks   []MessageHookFunc
+	requestsHooks  []RequestsHookFunc
+	responsesHooks []ResponsesHookFunc
+	logger         Printer
 }
 
 // NewServer returns new JSON-RPC 2.0 Server.
@@ -94,6 +142,14 @@ func NewServer(opts Options) Server {
 		opts.TargetURL = defaultTargetURL
 	}
 
+	if opts.OpenRPCInfo.Title == "" {
+		opts.OpenRPCInfo.Title = defaultAPITitle
+	}
+
+	if opts.OpenRPCInfo.Version == "" {
+		opts.OpenRPCInfo.Version = defaultAPIVersion
+	}
+
 	if opts.Upgrader == nil {
 		opts.Upgrader = &websocket.Upgrader{
 			CheckOrigin: func(r *http.Request) bool { return opts.AllowCORS },
@@ -101,8 +157,10 @@ func NewServer(opts Options) Server {
 	}
 
 	return Server{
-		services: make(map[string]Invoker),
-		options:  opts,
+		services:    make(map[string]Invoker),
+		schemas:     make(map[string]smd.Service),
+		definitions: make(map[string]smd.Definition),
+		options:     opts,
 	}
 }
 
@@ -111,9 +169,36 @@ func (s *Server) Use(m ...MiddlewareFunc) {
 	s.middleware = append(s.middleware, m...)
 }
 
+// UseMessageHook registers hooks for raw messages. Hooks are executed before parsing in order of registration.
+func (s *Server) UseMessageHook(h ...MessageHookFunc) {
+	s.messageHooks = append(s.messageHooks, h...)
+}
+
+// UseRequestsHook registers hooks for parsed requests. Hooks are executed before middleware in order of registration.
+func (s *Server) UseRequestsHook(h ...RequestsHookFunc) {
+	s.requestsHooks = append(s.requestsHooks, h...)
+}
+
+// UseResponsesHook registers hooks for collected responses. Hooks are executed after all requests in order of registration.
+func (s *Server) UseResponsesHook(h ...ResponsesHookFunc) {
+	s.responsesHooks = append(s.responsesHooks, h...)
+}
+
 // Register registers new service for given namespace. For public namespace use empty string.
 func (s *Server) Register(namespace string, service Invoker) {
-	s.services[strings.ToLower(namespace)] = service
+	namespace = strings.ToLower(namespace)
+	s.services[namespace] = service
+
+	if s.options.ValidateParams {
+		info := service.SMD()
+		for m, d := range info.Methods {
+			s.schemas[methodKey(namespace, strings.ToLower(m))] = d
+		}
+
+		for name, d := range info.Definitions {
+			s.definitions[name] = d
+		}
+	}
 }
 
 // RegisterAll registers all services listed in map.
@@ -130,6 +215,14 @@ func (s *Server) SetLogger(printer Printer) {
 
 // process process JSON-RPC 2.0 message, invokes correct method for namespace and returns JSON-RPC 2.0 Response.
 func (s *Server) process(ctx context.Context, message json.RawMessage) interface{} {
+	var err error
+	// run hooks for raw message
+	for _, h := range s.messageHooks {
+		if ctx, message, err = h(ctx, message); err != nil {
+			return newResponseFromError(nil, err)
+		}
+	}
+
 	var requests []Request
 	// parsing batch requests
 	batch := IsArray(message)
@@ -144,26 +237,71 @@ func (s *Server) process(ctx context.Context, message json.RawMessage) interface
 		return NewResponseError(nil, ParseError, "", nil)
 	}
 
-	// if there no requests to process
-	if len(requests) == 0 {
-		return NewResponseError(nil, InvalidRequest, "", nil)
-	} else if len(requests) > s.options.BatchMaxLen {
-		return NewResponseError(nil, InvalidRequest, "", "max requests length in batch exceeded")
+	if resp := s.checkBatch(requests); resp != nil {
+		return resp
+	}
+
+	// run hooks for parsed requests
+	for _, h := range s.requestsHooks {
+		if ctx, requests, err = h(ctx, requests); err != nil {
+			return newResponseFromError(nil, err)
+		}
+	}
+
+	// hooks could replace requests, so they are checked again
+	if len(s.requestsHooks) > 0 {
+		if resp := s.checkBatch(requests); resp != nil {
+			return resp
+		}
 	}
 
 	// process single request: if request single and not notification  - just run it and return result
-	if !batch && requests[0].ID != nil {
-		return s.processRequest(ctx, requests[0])
+	if !batch && len(requests) == 1 && requests[0].ID != nil {
+		if res := s.runResponsesHooks(ctx, requests, []Response{s.processRequest(ctx, requests[0])}); len(res) > 0 {
+			return res[0]
+		}
+
+		return nil
 	}
 
 	// process batch requests
-	if res := s.processBatch(ctx, requests); len(res) > 0 {
+	var responses []Response
+	if batch && s.options.BeginTx != nil {
+		responses = s.processTxBatch(ctx, requests)
+	} else {
+		responses = s.processBatch(ctx, requests)
+	}
+
+	if res := s.runResponsesHooks(ctx, requests, responses); len(res) > 0 {
 		return res
 	}
 
 	return nil
 }
 
+// checkBatch returns Invalid Request error if there are no requests to process or batch is too long.
+func (s *Server) checkBatch(requests []Request) *Response {
+	var resp Response
+	if len(requests) == 0 {
+		resp = NewResponseError(nil, InvalidRequest, "", nil)
+	} else if len(requests) > s.options.BatchMaxLen {
+		resp = NewResponseError(nil, InvalidRequest, "", "max requests length in batch exceeded")
+	} else {
+		return nil
+	}
+
+	return &resp
+}
+
+// runResponsesHooks runs hooks for collected responses.
+func (s Server) runResponsesHooks(ctx context.Context, requests []Request, responses []Response) []Response {
+	for _, h := range s.responsesHooks {
+		responses = h(ctx, requests, responses)
+	}
+
+	return responses
+}
+
 // processBatch process batch requests with context.
 func (s Server) processBatch(ctx context.Context, requests []Request) []Response {
 	reqLen := len(requests)
@@ -214,6 +352,12 @@ func (s Server) processRequest(ctx context.Context, req Request) Response {
 
 	// convert method to lower and find namespace
 	lowerM := strings.ToLower(req.Method)
+	if lowerM == openrpc.DiscoverMethod && s.options.ExposeSMD {
+		resp := Response{ID: req.ID}
+		resp.Set(s.OpenRPC())
+		return resp
+	}
+
 	sp := strings.SplitN(lowerM, ".", 2)
 	namespace, method := "", lowerM
 	if len(sp) == 2 {
@@ -232,6 +376,10 @@ func (s Server) processRequest(ctx context.Context, req Request) Response {
 
 	// set middleware to func
 	f := InvokeFunc(s.services[namespace].Invoke)
+	if s.options.ValidateParams {
+		f = s.validateParams(f)
+	}
+
 	for i := len(s.middleware) - 1; i >= 0; i-- {
 		f = s.middleware[i](f)
 	}
@@ -247,6 +395,28 @@ func (s Server) processRequest(ctx context.Context, req Request) Response {
 	return resp
 }
 
+// validateParams returns InvokeFunc which validates params against method schema before invoke.
+func (s Server) validateParams(next InvokeFunc) InvokeFunc {
+	return func(ctx context.Context, method string, params json.RawMessage) Response {
+		if sch, ok := s.schemas[methodKey(NamespaceFromContext(ctx), method)]; ok {
+			if errs := sch.ValidateParams(params, s.definitions); len(errs) > 0 {
+				return NewResponseError(nil, InvalidParams, "", errs)
+			}
+		}
+
+		return next(ctx, method, params)
+	}
+}
+
+// methodKey returns full method name with namespace.
+func methodKey(namespace, method string) string {
+	if namespace == "" {
+		return method
+	}
+
+	return namespace + "." + method
+}
+
 // Do process JSON-RPC 2.0 request, invokes correct method for namespace and returns JSON-RPC 2.0 Response or marshaller error.
 func (s Server) Do(ctx context.Context, req []byte) ([]byte, error) {
 	return json.Marshal(s.process(ctx, req))
@@ -280,11 +450,23 @@ func (s Server) SMD() smd.Schema {
 			sch.Services[method] = d
 			sch.Description += info.Description // TODO formatting
 		}
+
+		for name, d := range info.Definitions {
+			if sch.Definitions == nil {
+				sch.Definitions = make(map[string]smd.Definition)
+			}
+			sch.Definitions[name] = d
+		}
 	}
 
 	return sch
 }
 
+// OpenRPC returns OpenRPC document with all registered methods.
+func (s Server) OpenRPC() openrpc.Document {
+	return openrpc.FromSMD(s.SMD(), s.options.OpenRPCInfo)
+}
+
 // IsArray checks json message if it array or object.
 func IsArray(message json.RawMessage) bool {
 	for _, b := range message {
@@ -346,6 +528,29 @@ func ConvertToObject(keys []string, params json.RawMessage) (json.RawMessage, er
 	return buf.Bytes(), nil
 }
 
+// newResponseFromError returns new Response with Error object from given error.
+func newResponseFromError(id *json.RawMessage, err error) Response {
+	resp := Response{}
+	resp.Set(nil, err)
+	resp.ID = id
+
+	return resp
+}
+
+// newTransportContext creates new context with transport name.
+func newTransportContext(ctx context.Context, transport string) context.Context {
+	return context.WithValue(ctx, transportKey, transport)
+}
+
+// TransportFromContext returns transport name from context. Empty string means direct call via Server.Do.
+func TransportFromContext(ctx context.Context) string {
+	if r, ok := ctx.Value(transportKey).(string); ok {
+		return r
+	}
+
+	return ""
+}
+
 // newRequestContext creates new context with http.Request.
 func newRequestContext(ctx context.Context, req *http.Request) context.Context {
 	return context.WithValue(ctx, requestKey, req)
@@ -2,10 +2,18 @@ package zenrpc_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"regexp"
+	"slices"
 	"testing"
 
 	"github.com/semrush/zenrpc/v2"
+	"github.com/semrush/zenrpc/v2/openrpc"
+	"github.com/semrush/zenrpc/v2/smd"
 	"github.com/semrush/zenrpc/v2/testdata"
 )
 
@@ -25,3 +33,596 @@ func TestServer_SMD(t *testing.T) {
 		t.Error(string(b))
 	}
 }
+
+func TestServer_Hooks(t *testing.T) {
+	server := zenrpc.NewServer(zenrpc.Options{})
+	server.Register("arith", &testdata.ArithService{})
+
+	var batchLen int
+	server.UseMessageHook(func(ctx context.Context, message json.RawMessage) (context.Context, json.RawMessage, error) {
+		if bytes.Contains(message, []byte("forbidden")) {
+			return ctx, nil, zenrpc.NewStringError(403, "forbidden")
+		}
+
+		return ctx, bytes.Replace(message, []byte("arith.mul"), []byte("arith.multiply"), -1), nil
+	})
+	server.UseRequestsHook(func(ctx context.Context, requests []zenrpc.Request) (context.Context, []zenrpc.Request, error) {
+		if len(requests) > 2 {
+			return ctx, nil, zenrpc.NewStringError(429, "too many requests")
+		}
+
+		batchLen = len(requests)
+		switch requests[0].Method {
+		case "arith.empty":
+			return ctx, nil, nil
+		case "arith.expand":
+			return ctx, make([]zenrpc.Request, 11), nil // default BatchMaxLen is 10, nil
+		}
+
+		return ctx, requests, nil
+	})
+	server.UseResponsesHook(func(ctx context.Context, requests []zenrpc.Request, responses []zenrpc.Response) []zenrpc.Response {
+		for i := range responses {
+			responses[i].Extensions = map[string]interface{}{"batch": len(requests)}
+		}
+
+		return responses
+	})
+
+	var tc = []struct {
+		in, out string
+		len     int
+	}{
+		{
+			in:  `{"jsonrpc": "2.0", "method": "arith.mul", "params": { "a": 3, "b": 2 }, "id": 1 }`,
+			out: `{"jsonrpc":"2.0","id":1,"result":6,"extensions":{"batch":1}}`,
+			len: 1,
+		},
+		{
+			in:  `{"jsonrpc": "2.0", "method": "forbidden", "id": 1 }`,
+			out: `{"jsonrpc":"2.0","id":null,"error":{"code":403,"message":"forbidden"}}`,
+		},
+		{
+			in:  `[{"jsonrpc": "2.0", "method": "arith.mul", "params": { "a": 3, "b": 2 }, "id": 1 }, {"jsonrpc": "2.0", "method": "arith.pi"}]`,
+			out: `[{"jsonrpc":"2.0","id":1,"result":6,"extensions":{"batch":2}}]`,
+			len: 2,
+		},
+		{
+			in:  `[{"jsonrpc": "2.0", "method": "arith.pi"}, {"jsonrpc": "2.0", "method": "arith.pi"}, {"jsonrpc": "2.0", "method": "arith.pi"}]`,
+			out: `{"jsonrpc":"2.0","id":null,"error":{"code":429,"message":"too many requests"}}`,
+		},
+		{
+			in:  `[{"jsonrpc": "2.0", "method": "arith.empty", "id": 1 }]`,
+			out: `{"jsonrpc":"2.0","id":null,"error":{"code":-32600,"message":"Invalid Request"}}`,
+			len: 1,
+		},
+		{
+			in:  `{"jsonrpc": "2.0", "method": "arith.expand", "id": 1 }`,
+			out: `{"jsonrpc":"2.0","id":null,"error":{"code":-32600,"message":"Invalid Request","data":"max requests length in batch exceeded"}}`,
+			len: 1,
+		},
+	}
+
+	for _, c := range tc {
+		batchLen = 0
+		resp, err := server.Do(context.Background(), []byte(c.in))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(resp) != c.out {
+			t.Errorf("Input: %s\n got %s expected %s", c.in, resp, c.out)
+		}
+
+		if batchLen != c.len {
+			t.Errorf("Input: %s\n got %d requests in hook expected %d", c.in, batchLen, c.len)
+		}
+	}
+}
+
+type testTx struct {
+	committed, rolledBack bool
+}
+
+func (tx *testTx) Commit(ctx context.Context) error {
+	tx.committed = true
+	return nil
+}
+
+func (tx *testTx) Rollback(ctx context.Context) error {
+	tx.rolledBack = true
+	return nil
+}
+
+func TestServer_TxBatch(t *testing.T) {
+	var tx *testTx
+	server := zenrpc.NewServer(zenrpc.Options{BeginTx: func(ctx context.Context) (zenrpc.Tx, error) {
+		tx = &testTx{}
+		return tx, nil
+	}})
+	server.Register("arith", &testdata.ArithService{})
+	server.Use(func(h zenrpc.InvokeFunc) zenrpc.InvokeFunc {
+		return func(ctx context.Context, method string, params json.RawMessage) zenrpc.Response {
+			if _, ok := zenrpc.TxFromContext(ctx); !ok {
+				return zenrpc.NewResponseError(nil, zenrpc.InternalError, "", "transaction not found")
+			}
+
+			return h(ctx, method, params)
+		}
+	})
+
+	var tc = []struct {
+		in, out               string
+		committed, rolledBack bool
+	}{
+		{
+			in: `[{"jsonrpc": "2.0", "method": "arith.multiply", "params": { "a": 3, "b": 2 }, "id": 1 },
+				  {"jsonrpc": "2.0", "method": "arith.multiply", "params": { "a": 3, "b": 3 }, "id": 2 }]`,
+			out:       `[{"jsonrpc":"2.0","id":1,"result":6},{"jsonrpc":"2.0","id":2,"result":9}]`,
+			committed: true,
+		},
+		{
+			in: `[{"jsonrpc": "2.0", "method": "arith.multiply", "params": { "a": 3, "b": 2 }, "id": 1 },
+				  {"jsonrpc": "2.0", "method": "arith.checkerror", "params": [ true ]},
+				  {"jsonrpc": "2.0", "method": "arith.multiply", "params": { "a": 3, "b": 3 }, "id": 2 }]`,
+			out:        `[{"jsonrpc":"2.0","id":1,"error":{"code":-32000,"message":"transaction rolled back"}},{"jsonrpc":"2.0","id":2,"error":{"code":-32000,"message":"transaction rolled back"}}]`,
+			rolledBack: true,
+		},
+		{
+			in: `[{"jsonrpc": "2.0", "method": "arith.multiply", "params": { "a": 3, "b": 2 }, "id": 1 },
+				  {"jsonrpc": "2.0", "method": "arith.checkerror", "params": [ true ], "id": 2}]`,
+			out:        `[{"jsonrpc":"2.0","id":1,"error":{"code":-32000,"message":"transaction rolled back"}},{"jsonrpc":"2.0","id":2,"error":{"code":-32603,"message":"test"}}]`,
+			rolledBack: true,
+		},
+	}
+
+	for _, c := range tc {
+		resp, err := server.Do(context.Background(), []byte(c.in))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(resp) != c.out {
+			t.Errorf("Input: %s\n got %s expected %s", c.in, resp, c.out)
+		}
+
+		if tx.committed != c.committed || tx.rolledBack != c.rolledBack {
+			t.Errorf("Input: %s\n got committed=%v rolledBack=%v", c.in, tx.committed, tx.rolledBack)
+		}
+	}
+}
+
+func TestServer_Call(t *testing.T) {
+	var res int
+	if err := rpc.Call(context.Background(), "arith.multiply", map[string]int{"a": 3, "b": 2}, &res); err != nil {
+		t.Fatal(err)
+	} else if res != 6 {
+		t.Errorf("got %d expected 6", res)
+	}
+
+	var quo testdata.Quotient
+	if err := rpc.Call(context.Background(), "arith.divide", []int{7, 2}, &quo); err != nil {
+		t.Fatal(err)
+	} else if quo.Quo != 3 || quo.Rem != 1 {
+		t.Errorf("got %+v expected {Quo:3 Rem:1}", quo)
+	}
+
+	err := rpc.Call(context.Background(), "arith.divide", []int{1, 1}, &quo)
+	if e, ok := err.(*zenrpc.Error); !ok || e.Code != 401 {
+		t.Errorf("got %v expected 401 error", err)
+	}
+
+	if err := rpc.Call(context.Background(), "arith.unknown", nil, nil); err == nil || err.(*zenrpc.Error).Code != zenrpc.MethodNotFound {
+		t.Errorf("got %v expected method not found error", err)
+	}
+}
+
+func TestServer_CallBatch(t *testing.T) {
+	var mul int
+	var pow float64
+	calls := []*zenrpc.Call{
+		{Method: "arith.multiply", Args: []int{3, 3}, Result: &mul},
+		{Method: "arith.pow", Args: map[string]float64{"base": 3}, Result: &pow},
+		{Method: "arith.checkerror", Args: []bool{true}},
+	}
+
+	err := rpc.CallBatch(context.Background(), calls...)
+	if err == nil || err.Error() != "test" {
+		t.Errorf("got %v expected test error", err)
+	}
+
+	if mul != 9 || pow != 9 {
+		t.Errorf("got mul=%d pow=%v expected 9", mul, pow)
+	}
+
+	if calls[0].Error != nil || calls[1].Error != nil || calls[2].Error == nil {
+		t.Errorf("got errors %v, %v, %v", calls[0].Error, calls[1].Error, calls[2].Error)
+	}
+}
+
+func TestServer_OpenRPC(t *testing.T) {
+	server := zenrpc.NewServer(zenrpc.Options{ExposeSMD: true})
+	server.Register("arith", &testdata.ArithService{})
+
+	resp, err := server.Do(context.Background(), []byte(`{"jsonrpc": "2.0", "method": "rpc.discover", "id": 1 }`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var r struct {
+		Result struct {
+			OpenRPC string `json:"openrpc"`
+			Info    struct {
+				Title string `json:"title"`
+			} `json:"info"`
+			Methods []struct {
+				Name string `json:"name"`
+			} `json:"methods"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(resp, &r); err != nil {
+		t.Fatal(err)
+	}
+
+	if r.Result.OpenRPC == "" || r.Result.Info.Title != "zenrpc" || len(r.Result.Methods) != len(server.SMD().Services) {
+		t.Errorf("got %s", resp)
+	}
+
+	// discover is available only with exposed smd
+	resp, err = rpc.Do(context.Background(), []byte(`{"jsonrpc": "2.0", "method": "rpc.discover", "id": 1 }`))
+	if err != nil {
+		t.Fatal(err)
+	} else if expected := `{"jsonrpc":"2.0","id":1,"error":{"code":-32601,"message":"Method not found"}}`; string(resp) != expected {
+		t.Errorf("got %s expected %s", resp, expected)
+	}
+}
+
+func TestServer_OpenRPCDefaults(t *testing.T) {
+	server := zenrpc.NewServer(zenrpc.Options{ExposeSMD: true})
+	server.Register("phonebook", &testdata.PhoneBook{})
+
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	res, err := http.Get(ts.URL + "?smd=openrpc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	var doc openrpc.Document
+	if err := json.NewDecoder(res.Body).Decode(&doc); err != nil {
+		t.Fatal(err)
+	}
+
+	defaults := map[string]string{}
+	for _, m := range doc.Methods {
+		if m.Name != "phonebook.Get" {
+			continue
+		}
+
+		for _, p := range m.Params {
+			if p.Schema.Default != nil {
+				defaults[p.Name] = string(*p.Schema.Default)
+			}
+		}
+	}
+
+	if want := map[string]string{"page": "0", "count": "50"}; !reflect.DeepEqual(defaults, want) {
+		t.Errorf("got phonebook.Get defaults %v, want %v", defaults, want)
+	}
+}
+
+func TestServer_SMDDefinitions(t *testing.T) {
+	r := rpc.SMD()
+	b, err := json.Marshal(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	refs := regexp.MustCompile(`"\$ref":"#/definitions/([^"]+)"`).FindAllSubmatch(b, -1)
+	if len(refs) == 0 {
+		t.Fatalf("no refs in %s", b)
+	}
+
+	for _, ref := range refs {
+		if _, ok := r.Definitions[string(ref[1])]; !ok {
+			t.Errorf("definition %s not found", ref[1])
+		}
+	}
+
+	if d := r.Definitions["testdata.Quotient"]; !reflect.DeepEqual(d.Required, []string{"Quo", "rem"}) {
+		t.Errorf("got required %v", d.Required)
+	}
+
+	// pointer fields are nullable and not required
+	if person := (testdata.PhoneBook{}).SMD().Definitions["testdata.Person"]; !person.Properties["address"].Nullable || slices.Contains(person.Required, "address") {
+		t.Errorf("got Person %+v", person)
+	}
+
+	// nullable is expressed by oneOf with null type
+	address := (testdata.PhoneBook{}).SMD().Definitions["testdata.Person"].Properties["address"]
+	b, err = json.Marshal(address)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded smd.Property
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Contains(b, []byte(`{"type":"null"}`)) || bytes.Contains(b, []byte("nullable")) || !reflect.DeepEqual(decoded, address) {
+		t.Errorf("got address %s", b)
+	}
+
+	// polymorphic return is one of listed types
+	catalogue := (testdata.CatalogueService{}).SMD()
+	if returns := catalogue.Methods["Fourth"].Returns; returns.Type != smd.Any || len(returns.OneOf) != 2 || returns.OneOf[1].Ref != "#/definitions/testdata.Campaign" {
+		t.Errorf("got Fourth returns %+v", returns)
+	}
+
+	// mutually recursive structs are linked by refs
+	if items := catalogue.Definitions["testdata.SubGroup"].Properties["nodes"].Items; items == nil || items.Ref != "#/definitions/testdata.Group" {
+		t.Errorf("got SubGroup.nodes items %+v", items)
+	}
+}
+
+func TestServer_ValidateParams(t *testing.T) {
+	server := zenrpc.NewServer(zenrpc.Options{ValidateParams: true})
+	server.Register("arith", &testdata.ArithService{})
+	server.Register("phonebook", &testdata.PhoneBook{DB: testdata.People})
+
+	var tc = []struct {
+		in, out string
+	}{
+		{
+			in:  `{"jsonrpc": "2.0", "method": "arith.multiply", "params": { "a": 3, "b": 2 }, "id": 1 }`,
+			out: `{"jsonrpc":"2.0","id":1,"result":6}`},
+		{
+			in:  `{"jsonrpc": "2.0", "method": "arith.multiply", "params": { "a": 3 }, "id": 1 }`,
+			out: `{"jsonrpc":"2.0","id":1,"error":{"code":-32602,"message":"Invalid params","data":[{"field":"b","message":"is required"}]}}`},
+		{
+			in:  `{"jsonrpc": "2.0", "method": "arith.multiply", "params": [ "3", 2.5 ], "id": 1 }`,
+			out: `{"jsonrpc":"2.0","id":1,"error":{"code":-32602,"message":"Invalid params","data":[{"field":"a","message":"must be integer"},{"field":"b","message":"must be integer"}]}}`},
+		{
+			in:  `{"jsonrpc": "2.0", "method": "arith.pow", "params": { "base": 3 }, "id": 1 }`,
+			out: `{"jsonrpc":"2.0","id":1,"result":9}`},
+		{
+			in:  `{"jsonrpc": "2.0", "method": "phonebook.validatesearch", "params": { "search": { "ByPhone": "123", "ByAddress": { "City": true } } }, "id": 1 }`,
+			out: `{"jsonrpc":"2.0","id":1,"error":{"code":-32602,"message":"Invalid params","data":[{"field":"search.ByAddress.Street","message":"is required"},{"field":"search.ByAddress.City","message":"must be string"}]}}`},
+		{
+			in:  `{"jsonrpc": "2.0", "method": "phonebook.validatesearch", "params": { "search": { "byphone": "123", "ByAddress": { "City": "c", "Street": "s" } } }, "id": 1 }`,
+			out: `{"jsonrpc":"2.0","id":1,"result":{"ByName":null,"ByType":null,"ByPhone":"123","ByAddress":{"Street":"s","City":"c"}}}`},
+		{
+			in:  `{"jsonrpc": "2.0", "method": "phonebook.validatesearch", "params": { "search": [] }, "id": 1 }`,
+			out: `{"jsonrpc":"2.0","id":1,"error":{"code":-32602,"message":"Invalid params","data":[{"field":"search","message":"must be object"}]}}`},
+	}
+
+	for _, c := range tc {
+		resp, err := server.Do(context.Background(), []byte(c.in))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(resp) != c.out {
+			t.Errorf("Input: %s\n got %s expected %s", c.in, resp, c.out)
+		}
+	}
+}
+
+func TestServer_Constraints(t *testing.T) {
+	server := zenrpc.NewServer(zenrpc.Options{})
+	server.Register("phonebook", &testdata.PhoneBook{DB: testdata.People})
+
+	var tc = []struct {
+		in, out string
+	}{
+		{
+			in:  `{"jsonrpc": "2.0", "method": "phonebook.get", "params": { "search": {}, "count": 500 }, "id": 1 }`,
+			out: `{"jsonrpc":"2.0","id":1,"error":{"code":-32602,"message":"Invalid params","data":[{"field":"count","message":"must be less than or equal to 100"}]}}`},
+		{
+			in:  `{"jsonrpc": "2.0", "method": "phonebook.get", "params": [ {}, -1, 0 ], "id": 1 }`,
+			out: `{"jsonrpc":"2.0","id":1,"error":{"code":-32602,"message":"Invalid params","data":[{"field":"page","message":"must be greater than or equal to 0"},{"field":"count","message":"must be greater than or equal to 1"}]}}`},
+		{
+			in:  `{"jsonrpc": "2.0", "method": "phonebook.validatesearch", "params": { "search": { "ByType": "home", "ByPhone": "+1-800-142-31-22-00000" } }, "id": 1 }`,
+			out: `{"jsonrpc":"2.0","id":1,"error":{"code":-32602,"message":"Invalid params","data":[{"field":"search.ByPhone","message":"must be at most 20 characters long"},{"field":"search.ByType","message":"must be one of \"mobile\", \"work\""}]}}`},
+		{
+			in:  `{"jsonrpc": "2.0", "method": "phonebook.validatesearch", "params": { "search": { "ByPhone": "phone" } }, "id": 1 }`,
+			out: `{"jsonrpc":"2.0","id":1,"error":{"code":-32602,"message":"Invalid params","data":[{"field":"search.ByPhone","message":"must match pattern ^\\+?[0-9-]*$"}]}}`},
+		{
+			in:  `{"jsonrpc": "2.0", "method": "phonebook.validatesearch", "params": { "search": { "ByType": "work", "ByPhone": "+1-800" } }, "id": 1 }`,
+			out: `{"jsonrpc":"2.0","id":1,"result":{"ByName":null,"ByType":"work","ByPhone":"+1-800","ByAddress":null}}`},
+	}
+
+	for _, c := range tc {
+		resp, err := server.Do(context.Background(), []byte(c.in))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(resp) != c.out {
+			t.Errorf("Input: %s\n got %s expected %s", c.in, resp, c.out)
+		}
+	}
+
+	// constraints are exposed in SMD
+	if c := server.SMD().Services["phonebook.Get"].Parameters[2].Constraints; c.Minimum == nil || *c.Minimum != 1 || c.Maximum == nil || *c.Maximum != 100 {
+		t.Errorf("got constraints %+v", c)
+	}
+}
+
+func TestServer_VariadicAndInlineStructArgs(t *testing.T) {
+	server := zenrpc.NewServer(zenrpc.Options{ValidateParams: true})
+	server.Register("catalogue", &testdata.CatalogueService{})
+
+	var tc = []struct {
+		in, out string
+	}{
+		{
+			in:  `{"jsonrpc": "2.0", "method": "catalogue.fifth", "params": [ { "title": "t" }, [ 1, 2 ] ], "id": 1 }`,
+			out: `{"jsonrpc":"2.0","id":1,"result":[{"id":1,"title":"t","nodes":null,"group":null,"child":null,"sub":{"id":0,"title":"","nodes":null},"type":""},{"id":2,"title":"t","nodes":null,"group":null,"child":null,"sub":{"id":0,"title":"","nodes":null},"type":""}]}`},
+		{
+			in:  `{"jsonrpc": "2.0", "method": "catalogue.fifth", "params": { "filter": { "title": "t" } }, "id": 1 }`,
+			out: `{"jsonrpc":"2.0","id":1,"result":[]}`},
+		{
+			in:  `{"jsonrpc": "2.0", "method": "catalogue.fifth", "params": { "filter": {}, "ids": [ "1" ] }, "id": 1 }`,
+			out: `{"jsonrpc":"2.0","id":1,"error":{"code":-32602,"message":"Invalid params","data":[{"field":"filter.title","message":"is required"},{"field":"ids[0]","message":"must be integer"}]}}`},
+	}
+
+	for _, c := range tc {
+		resp, err := server.Do(context.Background(), []byte(c.in))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(resp) != c.out {
+			t.Errorf("Input: %s\n got %s expected %s", c.in, resp, c.out)
+		}
+	}
+}
+
+func TestServer_ResultObject(t *testing.T) {
+	server := zenrpc.NewServer(zenrpc.Options{})
+	server.Register("catalogue", &testdata.CatalogueService{})
+
+	var tc = []struct {
+		in, out string
+	}{
+		{
+			in:  `{"jsonrpc": "2.0", "method": "catalogue.stats", "params": [ [ { "group": [ {}, {}, {} ] }, { "group": [ {} ] } ] ], "id": 1 }`,
+			out: `{"jsonrpc":"2.0","id":1,"result":{"groups":4,"avg":2}}`},
+		{
+			in:  `{"jsonrpc": "2.0", "method": "catalogue.stats", "params": { "campaigns": [] }, "id": 1 }`,
+			out: `{"jsonrpc":"2.0","id":1,"error":{"code":-32603,"message":"no campaigns"}}`},
+	}
+
+	for _, c := range tc {
+		resp, err := server.Do(context.Background(), []byte(c.in))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(resp) != c.out {
+			t.Errorf("Input: %s\n got %s expected %s", c.in, resp, c.out)
+		}
+	}
+
+	if returns := server.SMD().Services["catalogue.Stats"].Returns; returns.Ref != "#/definitions/testdata.CatalogueService_StatsResult" {
+		t.Errorf("got Stats returns %+v", returns)
+	}
+}
+
+func TestServer_EmbeddedService(t *testing.T) {
+	server := zenrpc.NewServer(zenrpc.Options{})
+	server.Register("admin", &testdata.AdminPhoneBook{PhoneBook: testdata.PhoneBook{DB: map[uint64]*testdata.Person{
+		1: {ID: 1, FirstName: "John"},
+		2: {ID: 2, FirstName: "Jane", Deleted: true},
+	}}})
+
+	var tc = []struct {
+		in, out string
+	}{
+		{
+			in:  `{"jsonrpc": "2.0", "method": "admin.echo", "params": [ "hi" ], "id": 1 }`,
+			out: `{"jsonrpc":"2.0","id":1,"result":"admin: hi"}`},
+		{
+			in:  `{"jsonrpc": "2.0", "method": "admin.purge", "id": 1 }`,
+			out: `{"jsonrpc":"2.0","id":1,"result":1}`},
+		{
+			in:  `{"jsonrpc": "2.0", "method": "admin.delete", "params": [ 2 ], "id": 1 }`,
+			out: `{"jsonrpc":"2.0","id":1,"error":{"code":-32603,"message":"person was not found"}}`},
+	}
+
+	for _, c := range tc {
+		resp, err := server.Do(context.Background(), []byte(c.in))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(resp) != c.out {
+			t.Errorf("Input: %s\n got %s expected %s", c.in, resp, c.out)
+		}
+	}
+
+	if _, ok := server.SMD().Services["admin.Get"]; !ok {
+		t.Error("promoted method admin.Get is not found in SMD")
+	}
+}
+
+func TestServer_InterfaceService(t *testing.T) {
+	server := zenrpc.NewServer(zenrpc.Options{})
+	server.Register("contacts", testdata.NewContactsInvoker(testdata.ContactList{
+		{ID: 1, FirstName: "John"},
+		{ID: 2, FirstName: "Jane"},
+		{ID: 3, FirstName: "Bob"},
+	}))
+
+	var tc = []struct {
+		in, out string
+	}{
+		{
+			in:  `{"jsonrpc": "2.0", "method": "contacts.count", "id": 1 }`,
+			out: `{"jsonrpc":"2.0","id":1,"result":3}`},
+		{
+			in:  `{"jsonrpc": "2.0", "method": "contacts.find", "params": { "prefix": "Jo" }, "id": 1 }`,
+			out: `{"jsonrpc":"2.0","id":1,"result":[{"ID":1,"FirstName":"John","LastName":"","Phone":"","WorkPhone":null,"Mobile":null,"Deleted":false,"Addresses":null,"address":null}]}`},
+		{
+			in:  `{"jsonrpc": "2.0", "method": "contacts.find", "params": [ "" ], "id": 1 }`,
+			out: `{"jsonrpc":"2.0","id":1,"error":{"code":-32603,"message":"empty prefix"}}`},
+	}
+
+	for _, c := range tc {
+		resp, err := server.Do(context.Background(), []byte(c.in))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(resp) != c.out {
+			t.Errorf("Input: %s\n got %s expected %s", c.in, resp, c.out)
+		}
+	}
+
+	if _, ok := server.SMD().Services["contacts.Find"]; !ok {
+		t.Error("method contacts.Find is not found in SMD")
+	}
+}
+
+func TestServer_Enums(t *testing.T) {
+	server := zenrpc.NewServer(zenrpc.Options{ValidateParams: true})
+	server.Register("catalogue", &testdata.CatalogueService{})
+
+	// enum values of named types are exposed in SMD
+	definitions := server.SMD().Definitions
+	if enum := definitions["testdata.Group"].Properties["type"].Enum; !reflect.DeepEqual(enum, []interface{}{"default", "hidden"}) {
+		t.Errorf("got Group.type enum %v", enum)
+	}
+	if enum := definitions["testdata.Campaign"].Properties["status"].Enum; !reflect.DeepEqual(enum, []interface{}{1, 2, 3}) {
+		t.Errorf("got Campaign.status enum %v", enum)
+	}
+
+	var tc = []struct {
+		in, out string
+	}{
+		{
+			in:  `{"jsonrpc": "2.0", "method": "catalogue.second", "params": { "campaigns": [ { "id": 1, "group": [], "status": 2 } ] }, "id": 1 }`,
+			out: `{"jsonrpc":"2.0","id":1,"result":true}`},
+		{
+			in:  `{"jsonrpc": "2.0", "method": "catalogue.second", "params": { "campaigns": [ { "id": 1, "group": [ { "id": 1, "title": "t", "nodes": [], "group": [], "sub": { "id": 1, "title": "s", "nodes": [] }, "type": "visible" } ], "status": 5 } ] }, "id": 1 }`,
+			out: `{"jsonrpc":"2.0","id":1,"error":{"code":-32602,"message":"Invalid params","data":[{"field":"campaigns[0].group[0].type","message":"must be one of \"default\", \"hidden\""},{"field":"campaigns[0].status","message":"must be one of 1, 2, 3"}]}}`},
+		{
+			in:  `{"jsonrpc": "2.0", "method": "catalogue.first", "params": { "groups": [ { "id": 1, "title": "t", "nodes": [], "group": [], "type": "default", "sub": { "id": 1, "title": "s", "nodes": [ { "id": 2, "title": "n", "nodes": [], "group": [], "sub": { "id": 2, "title": "s", "nodes": [] }, "type": "visible" } ] } } ] }, "id": 1 }`,
+			out: `{"jsonrpc":"2.0","id":1,"error":{"code":-32602,"message":"Invalid params","data":[{"field":"groups[0].sub.nodes[0].type","message":"must be one of \"default\", \"hidden\""}]}}`},
+	}
+
+	for _, c := range tc {
+		resp, err := server.Do(context.Background(), []byte(c.in))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(resp) != c.out {
+			t.Errorf("Input: %s\n got %s expected %s", c.in, resp, c.out)
+		}
+	}
+}
@@ -9,6 +9,18 @@ const (
 	Boolean = "boolean"
 	Float   = "number"
 	Object  = "object"
+
+	// Null is type of null value, it is used in oneOf of nullable property and as return type of method without result.
+	Null = "null"
+
+	// Any is empty type of value which can be any JSON value.
+	Any = ""
+
+	// DateTime is format of RFC 3339 date-time string, e.g. time.Time.
+	DateTime = "date-time"
+
+	// Base64 is content encoding of binary data, e.g. []byte.
+	Base64 = "base64"
 )
 
 // Schema is struct for http://dojotoolkit.org/reference-guide/1.10/dojox/rpc/smd.html
@@ -38,6 +50,10 @@ type Schema struct {
 	// The property name represents the name of the service, and the value is the service description.
 	// This property MUST be included.
 	Services map[string]Service `json:"services"`
+
+	// Definitions contains JSON Schemas of named types shared by all services.
+	// Parameters, return values and properties refer to them as "#/definitions/<name>".
+	Definitions map[string]Definition `json:"definitions,omitempty"`
 }
 
 // Service is a web endpoint that can perform an action and/or return
@@ -64,32 +80,124 @@ type JSONSchema struct {
 	// If names are provided in the parameters this indicates that named parameters SHOULD be issued by
 	// the client making the service call, and the server MUST support named parameters,
 	// but positional parameters MAY be issued by the client and servers SHOULD support positional parameters.
-	Name        string                `json:"name,omitempty"`
-	Type        string                `json:"type,omitempty"`
-	Optional    bool                  `json:"optional,omitempty"`
-	Default     *json.RawMessage      `json:"default,omitempty"`
-	Description string                `json:"description,omitempty"`
-	Properties  map[string]Property   `json:"properties,omitempty"`
-	Definitions map[string]Definition `json:"definitions,omitempty"`
-	Items       map[string]string     `json:"items,omitempty"`
+	Name                 string              `json:"name,omitempty"`
+	Type                 string              `json:"type,omitempty"`
+	Optional             bool                `json:"optional,omitempty"`
+	Default              *json.RawMessage    `json:"default,omitempty"`
+	Description          string              `json:"description,omitempty"`
+	Ref                  string              `json:"$ref,omitempty"`
+	Format               string              `json:"format,omitempty"`
+	ContentEncoding      string              `json:"contentEncoding,omitempty"`
+	Properties           map[string]Property `json:"properties,omitempty"`
+	Required             []string            `json:"required,omitempty"`
+	AdditionalProperties *Property           `json:"additionalProperties,omitempty"`
+	Items                *Property           `json:"items,omitempty"`
+	OneOf                []Property          `json:"oneOf,omitempty"`
+	Constraints
 }
 
+// Property returns JSON Schema of parameter value without SMD specific fields.
+func (s JSONSchema) Property() Property {
+	return Property{
+		Ref:                  s.Ref,
+		Type:                 s.Type,
+		Description:          s.Description,
+		Format:               s.Format,
+		ContentEncoding:      s.ContentEncoding,
+		Properties:           s.Properties,
+		Required:             s.Required,
+		AdditionalProperties: s.AdditionalProperties,
+		Items:                s.Items,
+		OneOf:                s.OneOf,
+		Constraints:          s.Constraints,
+	}
+}
+
+// Property is a JSON Schema of struct property, array item or map value.
 type Property struct {
-	Type        string                `json:"type,omitempty"`
-	Description string                `json:"description,omitempty"`
-	Items       map[string]string     `json:"items,omitempty"`
-	Definitions map[string]Definition `json:"definitions,omitempty"`
-	Ref         string                `json:"$ref,omitempty"`
+	Ref                  string              `json:"$ref,omitempty"`
+	Type                 string              `json:"type,omitempty"`
+	Description          string              `json:"description,omitempty"`
+	Nullable             bool                `json:"-"` // struct property could be null, e.g. pointer, see MarshalJSON
+	Format               string              `json:"format,omitempty"`
+	ContentEncoding      string              `json:"contentEncoding,omitempty"`
+	Properties           map[string]Property `json:"properties,omitempty"`
+	Required             []string            `json:"required,omitempty"`
+	AdditionalProperties *Property           `json:"additionalProperties,omitempty"`
+	Items                *Property           `json:"items,omitempty"`
+	OneOf                []Property          `json:"oneOf,omitempty"`
+	Constraints
 }
 
-type Definition struct {
-	Type       string              `json:"type,omitempty"`
-	Properties map[string]Property `json:"properties,omitempty"`
+// property is Property with default JSON encoding.
+type property Property
+
+// MarshalJSON encodes nullable property as oneOf with null type, because JSON Schema has no nullable keyword.
+func (p Property) MarshalJSON() ([]byte, error) {
+	if !p.Nullable || p.Type == Any && p.Ref == "" && len(p.OneOf) == 0 {
+		return json.Marshal(property(p))
+	}
+
+	value := p
+	value.Nullable, value.Description = false, ""
+
+	return json.Marshal(property{Description: p.Description, OneOf: []Property{value, {Type: Null}}})
 }
 
+// UnmarshalJSON decodes property and restores Nullable from oneOf with null type.
+func (p *Property) UnmarshalJSON(data []byte) error {
+	var v property
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	*p = Property(v)
+	if len(v.OneOf) != 2 || v.OneOf[1].Type != Null || v.Type != Any || v.Ref != "" {
+		return nil
+	}
+
+	*p = v.OneOf[0]
+	p.Description, p.Nullable = v.Description, true
+
+	return nil
+}
+
+// Constraints are JSON Schema validation keywords for value. Zero lengths are treated as not set.
+type Constraints struct {
+	Minimum   *float64      `json:"minimum,omitempty"`
+	Maximum   *float64      `json:"maximum,omitempty"`
+	MinLength int           `json:"minLength,omitempty"`
+	MaxLength int           `json:"maxLength,omitempty"`
+	MinItems  int           `json:"minItems,omitempty"`
+	MaxItems  int           `json:"maxItems,omitempty"`
+	Pattern   string        `json:"pattern,omitempty"`
+	Enum      []interface{} `json:"enum,omitempty"`
+}
+
+// Definition is a JSON Schema of named type from Schema.Definitions.
+type Definition = Property
+
 type ServiceInfo struct {
 	Description string
 	Methods     map[string]Service
+
+	// Definitions contains named types used by service methods, they are merged into Schema.Definitions.
+	Definitions map[string]Definition
+}
+
+// Number returns pointer to v for Constraints.Minimum and Constraints.Maximum.
+func Number(v float64) *float64 {
+	return &v
+}
+
+// Default returns JSON encoded default value of parameter, it is used by generated code.
+func Default[T any](v T) *json.RawMessage {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+
+	return RawMessageString(string(data))
 }
 
 // RawMessageString returns string as *json.RawMessage.
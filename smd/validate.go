@@ -0,0 +1,323 @@
+package smd
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const definitionsPrefix = "#/definitions/"
+
+// FieldError is validation error of single parameter or its nested property.
+type FieldError struct {
+	// Field is path to invalid value, e.g. "p.Addresses[0].City".
+	Field string `json:"field"`
+
+	// Message describes validation failure.
+	Message string `json:"message"`
+}
+
+// ValidateParams validates JSON-RPC 2.0 params against service Parameters: required params and properties, types and constraints.
+// Named params are matched by parameter name, positional params are matched by index.
+// Definitions are used for resolving $ref. Null values are accepted as zero values.
+func (s Service) ValidateParams(params json.RawMessage, definitions map[string]Definition) []FieldError {
+	return s.validateParams(params, definitions, false)
+}
+
+// ValidateConstraints validates JSON-RPC 2.0 params against Constraints of service Parameters and their properties.
+// Unlike ValidateParams, missing values and values with unexpected types are skipped.
+func (s Service) ValidateConstraints(params json.RawMessage, definitions map[string]Definition) []FieldError {
+	return s.validateParams(params, definitions, true)
+}
+
+func (s Service) validateParams(params json.RawMessage, definitions map[string]Definition, constraintsOnly bool) []FieldError {
+	v := validator{definitions: definitions, constraintsOnly: constraintsOnly}
+
+	params = bytes.TrimSpace(params)
+	switch {
+	case len(params) == 0 || bytes.Equal(params, []byte("null")):
+		params = []byte("{}")
+	case params[0] == '[':
+		var values []json.RawMessage
+		if err := json.Unmarshal(params, &values); err != nil {
+			return v.invalid("", "params must be array or object")
+		}
+
+		if len(values) > len(s.Parameters) && !constraintsOnly {
+			return []FieldError{{Message: "invalid params number, expected " + strconv.Itoa(len(s.Parameters)) + ", got " + strconv.Itoa(len(values))}}
+		}
+
+		for i, p := range s.Parameters {
+			if i < len(values) {
+				v.validate(p.Name, values[i], p.Property())
+			} else if !p.Optional {
+				v.required(p.Name)
+			}
+		}
+
+		return v.errors
+	}
+
+	var values map[string]json.RawMessage
+	if err := json.Unmarshal(params, &values); err != nil {
+		return v.invalid("", "params must be array or object")
+	}
+
+	for _, p := range s.Parameters {
+		if value, ok := findValue(values, p.Name); ok {
+			v.validate(p.Name, value, p.Property())
+		} else if !p.Optional {
+			v.required(p.Name)
+		}
+	}
+
+	return v.errors
+}
+
+type validator struct {
+	definitions     map[string]Definition
+	constraintsOnly bool
+	errors          []FieldError
+}
+
+func (v *validator) add(field, message string) {
+	v.errors = append(v.errors, FieldError{Field: field, Message: message})
+}
+
+// required adds error for missing value.
+func (v *validator) required(field string) {
+	if !v.constraintsOnly {
+		v.add(field, "is required")
+	}
+}
+
+// invalid adds error for value with unexpected type and returns all errors.
+func (v *validator) invalid(field, message string) []FieldError {
+	if !v.constraintsOnly {
+		v.add(field, message)
+	}
+
+	return v.errors
+}
+
+// validate checks value against schema and collects errors.
+func (v *validator) validate(field string, value json.RawMessage, schema Property) {
+	if schema.Ref != "" {
+		d, ok := v.definitions[strings.TrimPrefix(schema.Ref, definitionsPrefix)]
+		if !ok {
+			return // unknown types are not validated
+		}
+		schema = d
+	}
+
+	value = bytes.TrimSpace(value)
+	if bytes.Equal(value, []byte("null")) {
+		return
+	}
+
+	switch schema.Type {
+	case String:
+		var s string
+		if json.Unmarshal(value, &s) != nil {
+			v.invalid(field, "must be string")
+			return
+		}
+
+		if !validFormat(s, schema.Format, schema.ContentEncoding) {
+			v.invalid(field, "must be "+formatName(schema.Format, schema.ContentEncoding)+" string")
+			return
+		}
+
+		v.validateString(field, s, schema.Constraints)
+	case Boolean:
+		var b bool
+		if json.Unmarshal(value, &b) != nil {
+			v.invalid(field, "must be boolean")
+		}
+	case Float, Integer:
+		var n json.Number
+		if value[0] == '"' || json.Unmarshal(value, &n) != nil {
+			v.invalid(field, "must be "+schema.Type)
+			return
+		}
+
+		if schema.Type == Integer && strings.ContainsAny(n.String(), ".eE") {
+			v.invalid(field, "must be "+schema.Type)
+			return
+		}
+
+		v.validateNumber(field, n, schema.Constraints)
+	case Array:
+		var items []json.RawMessage
+		if json.Unmarshal(value, &items) != nil {
+			v.invalid(field, "must be array")
+			return
+		}
+
+		if schema.MinItems > 0 && len(items) < schema.MinItems {
+			v.add(field, "must contain at least "+strconv.Itoa(schema.MinItems)+" items")
+		}
+
+		if schema.MaxItems > 0 && len(items) > schema.MaxItems {
+			v.add(field, "must contain at most "+strconv.Itoa(schema.MaxItems)+" items")
+		}
+
+		if schema.Items != nil {
+			for i, item := range items {
+				v.validate(field+"["+strconv.Itoa(i)+"]", item, *schema.Items)
+			}
+		}
+	case Object:
+		var properties map[string]json.RawMessage
+		if value[0] != '{' || json.Unmarshal(value, &properties) != nil {
+			v.invalid(field, "must be object")
+			return
+		}
+
+		for _, name := range schema.Required {
+			if _, ok := findValue(properties, name); !ok {
+				v.required(field + "." + name)
+			}
+		}
+
+		names := make([]string, 0, len(properties))
+		for name := range properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			if p, ok := findProperty(schema.Properties, name); ok {
+				v.validate(field+"."+name, properties[name], p)
+			} else if schema.AdditionalProperties != nil {
+				v.validate(field+"."+name, properties[name], *schema.AdditionalProperties)
+			}
+		}
+	}
+}
+
+// validateString checks string constraints.
+func (v *validator) validateString(field, s string, c Constraints) {
+	if l := utf8.RuneCountInString(s); c.MinLength > 0 && l < c.MinLength {
+		v.add(field, "must be at least "+strconv.Itoa(c.MinLength)+" characters long")
+	} else if c.MaxLength > 0 && l > c.MaxLength {
+		v.add(field, "must be at most "+strconv.Itoa(c.MaxLength)+" characters long")
+	}
+
+	if c.Pattern != "" {
+		if re, err := regexp.Compile(c.Pattern); err == nil && !re.MatchString(s) {
+			v.add(field, "must match pattern "+c.Pattern)
+		}
+	}
+
+	if len(c.Enum) > 0 && !inEnum(s, c.Enum) {
+		v.add(field, "must be one of "+enumString(c.Enum))
+	}
+}
+
+// validateNumber checks number constraints.
+func (v *validator) validateNumber(field string, n json.Number, c Constraints) {
+	f, err := n.Float64()
+	if err != nil {
+		return
+	}
+
+	if c.Minimum != nil && f < *c.Minimum {
+		v.add(field, "must be greater than or equal to "+strconv.FormatFloat(*c.Minimum, 'g', -1, 64))
+	}
+
+	if c.Maximum != nil && f > *c.Maximum {
+		v.add(field, "must be less than or equal to "+strconv.FormatFloat(*c.Maximum, 'g', -1, 64))
+	}
+
+	if len(c.Enum) > 0 && !inEnum(f, c.Enum) {
+		v.add(field, "must be one of "+enumString(c.Enum))
+	}
+}
+
+// validFormat checks string against known format or content encoding, unknown formats are not validated.
+func validFormat(s, format, contentEncoding string) bool {
+	switch {
+	case format == DateTime:
+		_, err := time.Parse(time.RFC3339Nano, s)
+		return err == nil
+	case contentEncoding == Base64:
+		_, err := base64.StdEncoding.DecodeString(s)
+		return err == nil
+	default:
+		return true
+	}
+}
+
+// formatName returns name of format or content encoding for error message.
+func formatName(format, contentEncoding string) string {
+	if format != "" {
+		return format
+	}
+
+	return contentEncoding
+}
+
+// inEnum checks that value is equal to one of enum values after JSON decoding.
+func inEnum(value interface{}, enum []interface{}) bool {
+	for _, e := range enum {
+		b, err := json.Marshal(e)
+		if err != nil {
+			continue
+		}
+
+		var decoded interface{}
+		if json.Unmarshal(b, &decoded) == nil && decoded == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// enumString returns enum values as comma separated JSON values.
+func enumString(enum []interface{}) string {
+	values := make([]string, 0, len(enum))
+	for _, e := range enum {
+		b, _ := json.Marshal(e)
+		values = append(values, string(b))
+	}
+
+	return strings.Join(values, ", ")
+}
+
+// findValue returns object value by name, names are matched case-insensitively like in json.Unmarshal.
+func findValue(values map[string]json.RawMessage, name string) (json.RawMessage, bool) {
+	if v, ok := values[name]; ok {
+		return v, true
+	}
+
+	for n, v := range values {
+		if strings.EqualFold(n, name) {
+			return v, true
+		}
+	}
+
+	return nil, false
+}
+
+// findProperty returns property schema by name, names are matched case-insensitively like in json.Unmarshal.
+func findProperty(properties map[string]Property, name string) (Property, bool) {
+	if p, ok := properties[name]; ok {
+		return p, true
+	}
+
+	for n, p := range properties {
+		if strings.EqualFold(n, name) {
+			return p, true
+		}
+	}
+
+	return Property{}, false
+}
@@ -0,0 +1,96 @@
+// Command smd2ts generates TypeScript client from SMD schema exposed by zenrpc server.
+//
+// Usage:
+//
+//	smd2ts [-o api.ts] <path to smd.json | http://localhost:9999/?smd>
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/semrush/zenrpc/v2/smd"
+)
+
+func main() {
+	output := flag.String("o", "", "output file, stdout if empty")
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, "SMD path or url is empty")
+		os.Exit(1)
+	}
+
+	schema, err := loadSchema(flag.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		os.Exit(1)
+	}
+
+	source, err := generate(schema)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		os.Exit(1)
+	}
+
+	if *output == "" {
+		os.Stdout.Write(source)
+		return
+	}
+
+	if err := os.WriteFile(*output, source, 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+// loadSchema reads SMD schema from file or url.
+func loadSchema(path string) (smd.Schema, error) {
+	var schema smd.Schema
+
+	var data []byte
+	var err error
+	if strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
+		data, err = download(path)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+
+	if err != nil {
+		return schema, err
+	}
+
+	err = json.Unmarshal(data, &schema)
+	return schema, err
+}
+
+// download returns response body for GET request.
+func download(url string) ([]byte, error) {
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected http status %s", resp.Status)
+	}
+
+	return io.ReadAll(resp.Body)
+}
+
+// generate renders TypeScript source for schema.
+func generate(schema smd.Schema) ([]byte, error) {
+	output := new(bytes.Buffer)
+	if err := tsTemplate.Execute(output, newPackage(schema)); err != nil {
+		return nil, err
+	}
+
+	return output.Bytes(), nil
+}
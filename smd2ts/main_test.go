@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"testing"
+
+	"github.com/semrush/zenrpc/v2"
+	"github.com/semrush/zenrpc/v2/smd"
+	"github.com/semrush/zenrpc/v2/testdata"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func Test_generate(t *testing.T) {
+	rpc := zenrpc.NewServer(zenrpc.Options{})
+	rpc.Register("arith", testdata.ArithService{})
+	rpc.Register("phonebook", testdata.PhoneBook{})
+	rpc.Register("catalogue", testdata.CatalogueService{})
+	rpc.Register("", testdata.PrintService{}) // public
+
+	schema := rpc.SMD()
+
+	// optional parameter followed by required one
+	schema.Services["order.Create"] = smd.Service{
+		Description: "Create creates order.",
+		Parameters: []smd.JSONSchema{
+			{Name: "coupon", Optional: true, Type: smd.String},
+			{Name: "items", Type: smd.Array, Items: &smd.Property{Type: smd.Integer}},
+			{Name: "comment", Optional: true, Type: smd.String},
+		},
+		Returns: smd.JSONSchema{Type: smd.Integer},
+	}
+
+	// any return value
+	schema.Services["order.Metadata"] = smd.Service{
+		Description: "Metadata returns order metadata.",
+		Parameters:  []smd.JSONSchema{{Name: "id", Type: smd.Integer}},
+		Returns:     smd.JSONSchema{Type: smd.Any},
+	}
+
+	// smd2ts reads schema from JSON
+	b, err := json.Marshal(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schema = smd.Schema{}
+	if err := json.Unmarshal(b, &schema); err != nil {
+		t.Fatal(err)
+	}
+
+	source, err := generate(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	golden := "testdata/client.ts.golden"
+	if *update {
+		if err := os.WriteFile(golden, source, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(source, want) {
+		t.Errorf("generated source differs from %s, run go test -update to update it:\n%s", golden, source)
+	}
+}
+
+func Test_methodName(t *testing.T) {
+	tests := map[string]string{
+		"arith.Multiply": "arithMultiply",
+		"Print":          "print",
+		".hidden.Get":    "hiddenGet",
+		"_private":       "private",
+		"2fa.Check":      "_2faCheck",
+		"":               "_",
+	}
+
+	for name, want := range tests {
+		if got := methodName(name); got != want {
+			t.Errorf("methodName(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,387 @@
+package main
+
+import (
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/semrush/zenrpc/v2/smd"
+)
+
+const definitionsPrefix = "#/definitions/"
+
+var (
+	identRegexp    = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+	nonIdentRegexp = regexp.MustCompile(`[^A-Za-z0-9]+`)
+
+	// reservedWords could not be used as parameter names in TypeScript.
+	reservedWords = map[string]struct{}{
+		"break": {}, "case": {}, "catch": {}, "class": {}, "const": {}, "continue": {}, "debugger": {}, "default": {},
+		"delete": {}, "do": {}, "else": {}, "enum": {}, "export": {}, "extends": {}, "false": {}, "finally": {},
+		"for": {}, "function": {}, "if": {}, "import": {}, "in": {}, "instanceof": {}, "new": {}, "null": {},
+		"return": {}, "super": {}, "switch": {}, "this": {}, "throw": {}, "true": {}, "try": {}, "typeof": {},
+		"var": {}, "void": {}, "while": {}, "with": {},
+	}
+
+	tsTemplate = template.Must(template.New("ts").
+			Funcs(template.FuncMap{"comment": comment}).
+			Parse(`// Code generated by smd2ts; DO NOT EDIT.
+{{ range .Interfaces }}
+{{ comment .Description "" }}export interface {{ .Name }} {
+{{- range .Fields }}
+{{ comment .Description "  " }}  {{ .Name }}{{ if .Optional }}?{{ end }}: {{ .Type }};
+{{- end }}
+}
+{{ end }}
+/** Error codes declared by service methods. */
+export type ErrorCode = {{ if .ErrorCodes }}{{ range $i, $e := .ErrorCodes }}{{ if $i }} | {{ end }}{{ $e }}{{ end }}{{ else }}never{{ end }};
+
+/** JSON-RPC 2.0 error returned by server. */
+export class RPCError extends Error {
+  constructor(public readonly code: ErrorCode | number, message: string, public readonly data?: unknown) {
+    super(message);
+  }
+}
+
+/** JSON-RPC 2.0 client with typed methods. */
+export class Client {
+  private id = 0;
+
+  constructor(private readonly url: string, private readonly init: RequestInit = {}) {}
+
+  async call<T>(method: string, params?: Record<string, unknown>): Promise<T> {
+    const response = await fetch(this.url, {
+      ...this.init,
+      method: "POST",
+      headers: { ...(this.init.headers as Record<string, string>), "Content-Type": "application/json" },
+      body: JSON.stringify({ jsonrpc: "2.0", id: ++this.id, method, params }),
+    });
+
+    const data = await response.json();
+    if (data.error) {
+      throw new RPCError(data.error.code, data.error.message, data.error.data);
+    }
+
+    return data.result as T;
+  }
+{{- range .Methods }}
+
+{{ comment .Description "  " }}  {{ .Name }}({{ range $i, $e := .Params }}{{ if $i }}, {{ end }}{{ .Name }}{{ if .Optional }}?{{ end }}: {{ .Type }}{{ end }}): Promise<{{ .Returns }}> {
+    return this.call<{{ .Returns }}>("{{ .RPCName }}"{{ if .Params }}, { {{ range $i, $e := .Params }}{{ if $i }}, {{ end }}{{ if ne .Name .JSONName }}{{ .JSONName }}: {{ end }}{{ .Name }}{{ end }} }{{ end }});
+  }
+{{- end }}
+}
+`))
+)
+
+// tsPackage is a view model for TypeScript template.
+type tsPackage struct {
+	Interfaces []tsInterface
+	Methods    []tsMethod
+	ErrorCodes []int
+}
+
+type tsInterface struct {
+	Name        string
+	Description string
+	Fields      []tsField
+}
+
+type tsField struct {
+	Name        string
+	JSONName    string
+	Type        string
+	Description string
+	Optional    bool
+}
+
+type tsMethod struct {
+	Name        string
+	RPCName     string
+	Description string
+	Params      []tsField
+	Returns     string
+}
+
+// newPackage converts SMD schema to view model.
+func newPackage(schema smd.Schema) tsPackage {
+	var pkg tsPackage
+	codes := make(map[int]struct{})
+
+	for _, name := range sortedKeys(schema.Services) {
+		service := schema.Services[name]
+		m := tsMethod{
+			Name:        methodName(name),
+			RPCName:     name,
+			Description: service.Description,
+			Returns:     schemaType(service.Returns),
+		}
+
+		if service.Returns.Optional {
+			m.Returns += " | null"
+		}
+
+		for _, p := range service.Parameters {
+			m.Params = append(m.Params, tsField{
+				Name:        paramName(p.Name),
+				JSONName:    p.Name,
+				Type:        schemaType(p),
+				Description: p.Description,
+				Optional:    p.Optional,
+			})
+		}
+
+		// optional parameters could not be followed by required ones in TypeScript
+		for i, required := len(m.Params)-1, false; i >= 0; i-- {
+			if m.Params[i].Optional && required {
+				m.Params[i].Optional = false
+				m.Params[i].Type += " | undefined"
+			}
+			required = required || !m.Params[i].Optional
+		}
+
+		methodCodes := make([]int, 0, len(service.Errors))
+		for code := range service.Errors {
+			methodCodes = append(methodCodes, code)
+			codes[code] = struct{}{}
+		}
+		sort.Ints(methodCodes)
+
+		for _, code := range methodCodes {
+			m.Description = strings.TrimSpace(m.Description + "\n@throws {RPCError} " + strings.TrimSpace(strconv.Itoa(code)+" "+service.Errors[code]))
+		}
+
+		pkg.Methods = append(pkg.Methods, m)
+	}
+
+	for _, name := range sortedDefinitions(schema.Definitions) {
+		d := schema.Definitions[name]
+		i := tsInterface{Name: refName(name), Description: d.Description}
+		for _, fieldName := range sortedProperties(d.Properties) {
+			p := d.Properties[fieldName]
+			i.Fields = append(i.Fields, tsField{
+				Name:        fieldKey(fieldName),
+				Type:        propertyType(p),
+				Description: p.Description,
+				Optional:    !isRequired(d.Required, fieldName),
+			})
+		}
+
+		pkg.Interfaces = append(pkg.Interfaces, i)
+	}
+
+	for code := range codes {
+		pkg.ErrorCodes = append(pkg.ErrorCodes, code)
+	}
+	sort.Ints(pkg.ErrorCodes)
+
+	return pkg
+}
+
+// schemaType returns TypeScript type for parameter or return value, null return value is void.
+func schemaType(s smd.JSONSchema) string {
+	if s.Type == smd.Null {
+		return "void"
+	}
+
+	return propertyType(s.Property())
+}
+
+// propertyType returns TypeScript type for struct property, array item or map value.
+func propertyType(p smd.Property) string {
+	if p.Nullable {
+		p.Nullable = false
+		return propertyType(p) + " | null"
+	}
+
+	if p.Ref != "" {
+		return refName(p.Ref)
+	}
+
+	if len(p.OneOf) > 0 {
+		types := []string{}
+		for _, t := range p.OneOf {
+			types = append(types, propertyType(t))
+		}
+
+		return strings.Join(types, " | ")
+	}
+
+	if len(p.Enum) > 0 {
+		values := []string{}
+		for _, v := range p.Enum {
+			b, err := json.Marshal(v)
+			if err != nil {
+				return primitiveType(p.Type)
+			}
+			values = append(values, string(b))
+		}
+
+		return strings.Join(values, " | ")
+	}
+
+	switch p.Type {
+	case smd.Array:
+		if p.Items == nil {
+			return "unknown[]"
+		}
+
+		t := propertyType(*p.Items)
+		if strings.Contains(t, " | ") {
+			t = "(" + t + ")"
+		}
+
+		return t + "[]"
+	case smd.Object:
+		if len(p.Properties) > 0 {
+			fields := []string{}
+			for _, name := range sortedProperties(p.Properties) {
+				optional := ""
+				if !isRequired(p.Required, name) {
+					optional = "?"
+				}
+				fields = append(fields, fieldKey(name)+optional+": "+propertyType(p.Properties[name]))
+			}
+
+			return "{ " + strings.Join(fields, "; ") + " }"
+		}
+
+		if p.AdditionalProperties != nil {
+			return "Record<string, " + propertyType(*p.AdditionalProperties) + ">"
+		}
+
+		return "Record<string, unknown>"
+	default:
+		return primitiveType(p.Type)
+	}
+}
+
+// isRequired checks that property is listed in required properties.
+func isRequired(required []string, name string) bool {
+	for _, r := range required {
+		if r == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// primitiveType returns TypeScript type for JSON Schema primitive type.
+func primitiveType(t string) string {
+	switch t {
+	case smd.String:
+		return "string"
+	case smd.Integer, smd.Float:
+		return "number"
+	case smd.Boolean:
+		return "boolean"
+	case smd.Null:
+		return "null"
+	default:
+		return "unknown"
+	}
+}
+
+// refName converts definition name or reference like #/definitions/model.Point to interface name like ModelPoint.
+func refName(ref string) string {
+	result := ""
+	for _, part := range nonIdentRegexp.Split(strings.TrimPrefix(ref, definitionsPrefix), -1) {
+		result += strings.Title(part)
+	}
+
+	return result
+}
+
+// methodName converts SMD service name like arith.Multiply to method name like arithMultiply.
+// Name which is not valid identifier, e.g. empty or starting with digit, is prefixed with underscore.
+func methodName(name string) string {
+	result := ""
+	for _, part := range nonIdentRegexp.Split(name, -1) {
+		if part == "" {
+			continue
+		}
+
+		if result == "" {
+			result += strings.ToLower(part[:1]) + part[1:]
+		} else {
+			result += strings.Title(part)
+		}
+	}
+
+	if !identRegexp.MatchString(result) {
+		result = "_" + result
+	}
+
+	return result
+}
+
+// paramName returns safe TypeScript parameter name.
+func paramName(name string) string {
+	if _, ok := reservedWords[name]; ok || !identRegexp.MatchString(name) {
+		return nonIdentRegexp.ReplaceAllString(name, "_") + "_"
+	}
+
+	return name
+}
+
+// fieldKey returns property name quoted if needed.
+func fieldKey(name string) string {
+	if identRegexp.MatchString(name) {
+		return name
+	}
+
+	return `"` + name + `"`
+}
+
+// comment formats text as JSDoc comment with indent.
+func comment(text, indent string) string {
+	if text == "" {
+		return ""
+	}
+
+	lines := strings.Split(text, "\n")
+	if len(lines) == 1 {
+		return indent + "/** " + text + " */\n"
+	}
+
+	result := indent + "/**\n"
+	for _, line := range lines {
+		result += indent + " * " + line + "\n"
+	}
+
+	return result + indent + " */\n"
+}
+
+func sortedKeys(m map[string]smd.Service) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+func sortedDefinitions(m map[string]smd.Definition) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+func sortedProperties(m map[string]smd.Property) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
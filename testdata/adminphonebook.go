@@ -0,0 +1,29 @@
+package testdata
+
+import (
+	"github.com/semrush/zenrpc/v2"
+)
+
+// AdminPhoneBook is phonebook with administrative methods.
+type AdminPhoneBook struct {
+	PhoneBook
+	zenrpc.Service
+}
+
+// Purge removes deleted persons from phonebook and returns their number.
+func (pb AdminPhoneBook) Purge() int {
+	purged := 0
+	for id, p := range pb.DB {
+		if p.Deleted {
+			delete(pb.DB, id)
+			purged++
+		}
+	}
+
+	return purged
+}
+
+// Echo returns string with admin prefix, it shadows PhoneBook.Echo.
+func (pb *AdminPhoneBook) Echo(str string) string {
+	return "admin: " + str
+}
@@ -114,4 +114,4 @@ func (as *ArithService) SumArray(array *[]float64) float64 {
 	return sum
 }
 
-//go:generate zenrpc
+//go:generate zenrpc -client
@@ -1,29 +1,50 @@
 package testdata
 
 import (
+	"errors"
+
 	"github.com/semrush/zenrpc/v2"
 )
 
 type Group struct {
-	Id       int      `json:"id"`
-	Title    string   `json:"title"`
-	Nodes    []Group  `json:"nodes"`
-	Groups   []Group  `json:"group"`
-	ChildOpt *Group   `json:"child"`
-	Sub      SubGroup `json:"sub"`
+	Id       int       `json:"id"`
+	Title    string    `json:"title"`
+	Nodes    []Group   `json:"nodes"`
+	Groups   []Group   `json:"group"`
+	ChildOpt *Group    `json:"child"`
+	Sub      SubGroup  `json:"sub"`
+	Type     GroupType `json:"type"`
 }
 
+// GroupType is a type of group.
+type GroupType string
+
+const (
+	GroupTypeDefault GroupType = "default"
+	GroupTypeHidden  GroupType = "hidden"
+)
+
 type SubGroup struct {
-	Id    int    `json:"id"`
-	Title string `json:"title"`
-	//Nodes []Group `json:"nodes"` TODO still causes infinite recursion
+	Id    int     `json:"id"`
+	Title string  `json:"title"`
+	Nodes []Group `json:"nodes"`
 }
 
 type Campaign struct {
-	Id     int     `json:"id"`
-	Groups []Group `json:"group"`
+	Id     int            `json:"id"`
+	Groups []Group        `json:"group"`
+	Status CampaignStatus `json:"status"`
 }
 
+// CampaignStatus is a status of campaign.
+type CampaignStatus int
+
+const (
+	CampaignStatusActive CampaignStatus = iota + 1
+	CampaignStatusPaused
+	CampaignStatusArchived
+)
+
 type CatalogueService struct{ zenrpc.Service }
 
 func (s CatalogueService) First(groups []Group) (bool, error) {
@@ -38,4 +59,41 @@ func (s CatalogueService) Third() (Campaign, error) {
 	return Campaign{}, nil
 }
 
+// Fourth returns group for even id and campaign for odd id.
+//
+//zenrpc:return oneOf=Group,Campaign group or campaign
+func (s CatalogueService) Fourth(id int) (interface{}, error) {
+	if id%2 == 0 {
+		return Group{Id: id}, nil
+	}
+
+	return Campaign{Id: id}, nil
+}
+
+// Fifth returns groups with given ids matching filter.
+func (s CatalogueService) Fifth(filter struct {
+	Title string `json:"title"`
+	Type  *GroupType
+}, ids ...int) ([]Group, error) {
+	groups := []Group{}
+	for _, id := range ids {
+		groups = append(groups, Group{Id: id, Title: filter.Title})
+	}
+
+	return groups, nil
+}
+
+// Stats returns total and average number of groups in campaigns.
+func (s CatalogueService) Stats(campaigns []Campaign) (groups int, avg float64, err error) {
+	if len(campaigns) == 0 {
+		return 0, 0, errors.New("no campaigns")
+	}
+
+	for _, c := range campaigns {
+		groups += len(c.Groups)
+	}
+
+	return groups, float64(groups) / float64(len(campaigns)), nil
+}
+
 //go:generate zenrpc
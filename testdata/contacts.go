@@ -0,0 +1,40 @@
+package testdata
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// Contacts is contract of contacts service, it is implemented by ContactList.
+// zenrpc
+type Contacts interface {
+	// Find returns persons with first name starting with prefix.
+	//zenrpc:prefix name prefix
+	Find(ctx context.Context, prefix string) ([]Person, error)
+
+	// Count returns number of persons.
+	Count(ctx context.Context) (int, error)
+}
+
+// ContactList is slice based implementation of Contacts.
+type ContactList []Person
+
+func (l ContactList) Find(_ context.Context, prefix string) ([]Person, error) {
+	if prefix == "" {
+		return nil, errors.New("empty prefix")
+	}
+
+	result := []Person{}
+	for _, p := range l {
+		if strings.HasPrefix(p.FirstName, prefix) {
+			result = append(result, p)
+		}
+	}
+
+	return result, nil
+}
+
+func (l ContactList) Count(context.Context) (int, error) {
+	return len(l), nil
+}
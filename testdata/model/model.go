@@ -3,8 +3,11 @@ package model
 import "github.com/semrush/zenrpc/v2/testdata/objects"
 
 type Point struct {
-	objects.AbstractObject     // embeded object
-	X, Y                   int // coordinate
-	Z                      int `json:"-"`
+	objects.AbstractObject            // embeded object
+	X, Y                   Coordinate // coordinate
+	Z                      int        `json:"-"`
 	ConnectedObject        objects.AbstractObject
 }
+
+// Coordinate is a point coordinate.
+type Coordinate int
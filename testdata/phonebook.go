@@ -71,11 +71,14 @@ type Person struct {
 	AltAddress *Address `json:"address"`
 }
 
+// PersonID is unique identifier of person.
+type PersonID = uint64
+
 type PersonSearch struct {
 	// ByName is filter for searching person by first name or last name.
 	ByName    *string
-	ByType    *string
-	ByPhone   string
+	ByType    *string `zenrpc:"enum=mobile,work"`
+	ByPhone   string  `zenrpc:"pattern=^\\+?[0-9-]*$ maxLength=20"`
 	ByAddress *Address
 }
 
@@ -90,8 +93,8 @@ type PhoneBook struct {
 } //zenrpc
 
 // Get returns all people from DB.
-//zenrpc:page=0 current page
-//zenrpc:count=50 page size
+//zenrpc:page=0 min=0 current page
+//zenrpc:count=50 min=1 max=100 page size
 func (pb PhoneBook) Get(search PersonSearch, page, count *int) (res []*Person) {
 	for _, p := range pb.DB {
 		res = append(res, p)
@@ -109,7 +112,7 @@ func (pb PhoneBook) ValidateSearch(search *PersonSearch) *PersonSearch {
 // ById returns Person from DB.
 //zenrpc:id person id
 //zenrpc:404 person was not found
-func (pb PhoneBook) ById(id uint64) (*Person, *zenrpc.Error) {
+func (pb PhoneBook) ById(id PersonID) (*Person, *zenrpc.Error) {
 	if p, ok := pb.DB[id]; ok {
 		return p, nil
 	}
@@ -75,78 +75,14 @@ func (SubArithService) SMD() smd.ServiceInfo {
 						Optional:    false,
 						Description: ``,
 						Type:        smd.Object,
-						Properties: map[string]smd.Property{
-							"Name": {
-								Description: ``,
-								Type:        smd.String,
-							},
-							"SomeField": {
-								Description: ``,
-								Type:        smd.String,
-							},
-							"Measure": {
-								Description: ``,
-								Type:        smd.Float,
-							},
-							"A": {
-								Description: `coordinate`,
-								Type:        smd.Integer,
-							},
-							"B": {
-								Description: `coordinate`,
-								Type:        smd.Integer,
-							},
-							"when": {
-								Description: `when it happened`,
-								Ref:         "#/definitions/time.Time",
-								Type:        smd.Object,
-							},
-						},
-						Definitions: map[string]smd.Definition{
-							"time.Time": {
-								Type:       "object",
-								Properties: map[string]smd.Property{},
-							},
-						},
+						Ref:         "#/definitions/subarithservice.Point",
 					},
 				},
 				Returns: smd.JSONSchema{
 					Description: ``,
 					Optional:    false,
 					Type:        smd.Object,
-					Properties: map[string]smd.Property{
-						"Name": {
-							Description: ``,
-							Type:        smd.String,
-						},
-						"SomeField": {
-							Description: ``,
-							Type:        smd.String,
-						},
-						"Measure": {
-							Description: ``,
-							Type:        smd.Float,
-						},
-						"A": {
-							Description: `coordinate`,
-							Type:        smd.Integer,
-						},
-						"B": {
-							Description: `coordinate`,
-							Type:        smd.Integer,
-						},
-						"when": {
-							Description: `when it happened`,
-							Ref:         "#/definitions/time.Time",
-							Type:        smd.Object,
-						},
-					},
-					Definitions: map[string]smd.Definition{
-						"time.Time": {
-							Type:       "object",
-							Properties: map[string]smd.Property{},
-						},
-					},
+					Ref:         "#/definitions/subarithservice.Point",
 				},
 			},
 			"GetPoints": {
@@ -156,57 +92,9 @@ func (SubArithService) SMD() smd.ServiceInfo {
 					Description: ``,
 					Optional:    false,
 					Type:        smd.Array,
-					Items: map[string]string{
-						"$ref": "#/definitions/model.Point",
-					},
-					Definitions: map[string]smd.Definition{
-						"model.Point": {
-							Type: "object",
-							Properties: map[string]smd.Property{
-								"Name": {
-									Description: ``,
-									Type:        smd.String,
-								},
-								"SomeField": {
-									Description: ``,
-									Type:        smd.String,
-								},
-								"Measure": {
-									Description: ``,
-									Type:        smd.Float,
-								},
-								"X": {
-									Description: `coordinate`,
-									Type:        smd.Integer,
-								},
-								"Y": {
-									Description: `coordinate`,
-									Type:        smd.Integer,
-								},
-								"ConnectedObject": {
-									Description: ``,
-									Ref:         "#/definitions/objects.AbstractObject",
-									Type:        smd.Object,
-								},
-							},
-						},
-						"objects.AbstractObject": {
-							Type: "object",
-							Properties: map[string]smd.Property{
-								"Name": {
-									Description: ``,
-									Type:        smd.String,
-								},
-								"SomeField": {
-									Description: ``,
-									Type:        smd.String,
-								},
-								"Measure": {
-									Description: ``,
-									Type:        smd.Float,
-								},
-							},
-						},
+					Items: &smd.Property{
+						Type: smd.Object,
+						Ref:  "#/definitions/model.Point",
 					},
 				},
 			},
@@ -217,44 +105,9 @@ func (SubArithService) SMD() smd.ServiceInfo {
 					Description: ``,
 					Optional:    false,
 					Type:        smd.Array,
-					Items: map[string]string{
-						"$ref": "#/definitions/Point",
-					},
-					Definitions: map[string]smd.Definition{
-						"Point": {
-							Type: "object",
-							Properties: map[string]smd.Property{
-								"Name": {
-									Description: ``,
-									Type:        smd.String,
-								},
-								"SomeField": {
-									Description: ``,
-									Type:        smd.String,
-								},
-								"Measure": {
-									Description: ``,
-									Type:        smd.Float,
-								},
-								"A": {
-									Description: `coordinate`,
-									Type:        smd.Integer,
-								},
-								"B": {
-									Description: `coordinate`,
-									Type:        smd.Integer,
-								},
-								"when": {
-									Description: `when it happened`,
-									Ref:         "#/definitions/time.Time",
-									Type:        smd.Object,
-								},
-							},
-						},
-						"time.Time": {
-							Type:       "object",
-							Properties: map[string]smd.Property{},
-						},
+					Items: &smd.Property{
+						Type: smd.Object,
+						Ref:  "#/definitions/subarithservice.Point",
 					},
 				},
 			},
@@ -266,104 +119,14 @@ func (SubArithService) SMD() smd.ServiceInfo {
 						Optional:    false,
 						Description: ``,
 						Type:        smd.Object,
-						Properties: map[string]smd.Property{
-							"Name": {
-								Description: ``,
-								Type:        smd.String,
-							},
-							"SomeField": {
-								Description: ``,
-								Type:        smd.String,
-							},
-							"Measure": {
-								Description: ``,
-								Type:        smd.Float,
-							},
-							"X": {
-								Description: `coordinate`,
-								Type:        smd.Integer,
-							},
-							"Y": {
-								Description: `coordinate`,
-								Type:        smd.Integer,
-							},
-							"ConnectedObject": {
-								Description: ``,
-								Ref:         "#/definitions/objects.AbstractObject",
-								Type:        smd.Object,
-							},
-						},
-						Definitions: map[string]smd.Definition{
-							"objects.AbstractObject": {
-								Type: "object",
-								Properties: map[string]smd.Property{
-									"Name": {
-										Description: ``,
-										Type:        smd.String,
-									},
-									"SomeField": {
-										Description: ``,
-										Type:        smd.String,
-									},
-									"Measure": {
-										Description: ``,
-										Type:        smd.Float,
-									},
-								},
-							},
-						},
+						Ref:         "#/definitions/model.Point",
 					},
 				},
 				Returns: smd.JSONSchema{
 					Description: ``,
 					Optional:    false,
 					Type:        smd.Object,
-					Properties: map[string]smd.Property{
-						"Name": {
-							Description: ``,
-							Type:        smd.String,
-						},
-						"SomeField": {
-							Description: ``,
-							Type:        smd.String,
-						},
-						"Measure": {
-							Description: ``,
-							Type:        smd.Float,
-						},
-						"X": {
-							Description: `coordinate`,
-							Type:        smd.Integer,
-						},
-						"Y": {
-							Description: `coordinate`,
-							Type:        smd.Integer,
-						},
-						"ConnectedObject": {
-							Description: ``,
-							Ref:         "#/definitions/objects.AbstractObject",
-							Type:        smd.Object,
-						},
-					},
-					Definitions: map[string]smd.Definition{
-						"objects.AbstractObject": {
-							Type: "object",
-							Properties: map[string]smd.Property{
-								"Name": {
-									Description: ``,
-									Type:        smd.String,
-								},
-								"SomeField": {
-									Description: ``,
-									Type:        smd.String,
-								},
-								"Measure": {
-									Description: ``,
-									Type:        smd.Float,
-								},
-							},
-						},
-					},
+					Ref:         "#/definitions/model.Point",
 				},
 			},
 			"Multiply": {
@@ -398,6 +161,7 @@ func (SubArithService) SMD() smd.ServiceInfo {
 						Type:        smd.Boolean,
 					},
 				},
+				Returns: smd.JSONSchema{Type: smd.Null},
 				Errors: map[int]string{
 					500: "test error",
 				},
@@ -412,6 +176,7 @@ func (SubArithService) SMD() smd.ServiceInfo {
 						Type:        smd.Boolean,
 					},
 				},
+				Returns: smd.JSONSchema{Type: smd.Null},
 				Errors: map[int]string{
 					500: "test error",
 				},
@@ -422,7 +187,7 @@ func (SubArithService) SMD() smd.ServiceInfo {
 					{
 						Name:        "a",
 						Optional:    false,
-						Description: `the a`,
+						Description: ``,
 						Type:        smd.Integer,
 					},
 					{
@@ -436,16 +201,7 @@ func (SubArithService) SMD() smd.ServiceInfo {
 					Description: ``,
 					Optional:    true,
 					Type:        smd.Object,
-					Properties: map[string]smd.Property{
-						"Quo": {
-							Description: `Quo docs`,
-							Type:        smd.Integer,
-						},
-						"rem": {
-							Description: `Rem docs`,
-							Type:        smd.Integer,
-						},
-					},
+					Ref:         "#/definitions/subarithservice.Quotient",
 				},
 				Errors: map[int]string{
 					401:    "we do not serve 1",
@@ -464,6 +220,7 @@ func (SubArithService) SMD() smd.ServiceInfo {
 					{
 						Name:        "exp",
 						Optional:    true,
+						Default:     smd.Default[float64](2),
 						Description: `exponent could be empty`,
 						Type:        smd.Float,
 					},
@@ -489,10 +246,11 @@ func (SubArithService) SMD() smd.ServiceInfo {
 					{
 						Name:        "array",
 						Optional:    true,
+						Default:     smd.Default[[]float64]([]float64{1, 2, 4}),
 						Description: ``,
 						Type:        smd.Array,
-						Items: map[string]string{
-							"type": smd.Float,
+						Items: &smd.Property{
+							Type: smd.Float,
 						},
 					},
 				},
@@ -503,6 +261,103 @@ func (SubArithService) SMD() smd.ServiceInfo {
 				},
 			},
 		},
+		Definitions: map[string]smd.Definition{
+			"subarithservice.Point": {
+				Type: smd.Object,
+				Properties: map[string]smd.Property{
+					"Name": {
+						Description: ``,
+						Type:        smd.String,
+					},
+					"SomeField": {
+						Description: ``,
+						Type:        smd.String,
+					},
+					"Measure": {
+						Description: ``,
+						Type:        smd.Float,
+					},
+					"A": {
+						Description: `coordinate`,
+						Type:        smd.Integer,
+					},
+					"B": {
+						Description: `coordinate`,
+						Type:        smd.Integer,
+					},
+					"when": {
+						Description: `when it happened`,
+						Nullable:    true,
+						Type:        smd.String,
+						Format:      "date-time",
+					},
+				},
+				Required: []string{"Name", "SomeField", "Measure", "A", "B"},
+			},
+			"model.Point": {
+				Type: smd.Object,
+				Properties: map[string]smd.Property{
+					"Name": {
+						Description: ``,
+						Type:        smd.String,
+					},
+					"SomeField": {
+						Description: ``,
+						Type:        smd.String,
+					},
+					"Measure": {
+						Description: ``,
+						Type:        smd.Float,
+					},
+					"X": {
+						Description: `coordinate`,
+						Type:        smd.Integer,
+					},
+					"Y": {
+						Description: `coordinate`,
+						Type:        smd.Integer,
+					},
+					"ConnectedObject": {
+						Description: ``,
+						Type:        smd.Object,
+						Ref:         "#/definitions/objects.AbstractObject",
+					},
+				},
+				Required: []string{"Name", "SomeField", "Measure", "X", "Y", "ConnectedObject"},
+			},
+			"objects.AbstractObject": {
+				Type: smd.Object,
+				Properties: map[string]smd.Property{
+					"Name": {
+						Description: ``,
+						Type:        smd.String,
+					},
+					"SomeField": {
+						Description: ``,
+						Type:        smd.String,
+					},
+					"Measure": {
+						Description: ``,
+						Type:        smd.Float,
+					},
+				},
+				Required: []string{"Name", "SomeField", "Measure"},
+			},
+			"subarithservice.Quotient": {
+				Type: smd.Object,
+				Properties: map[string]smd.Property{
+					"Quo": {
+						Description: `Quo docs`,
+						Type:        smd.Integer,
+					},
+					"rem": {
+						Description: `Rem docs`,
+						Type:        smd.Integer,
+					},
+				},
+				Required: []string{"Quo", "rem"},
+			},
+		},
 	}
 }
 
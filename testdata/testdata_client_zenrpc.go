@@ -0,0 +1,407 @@
+// Code generated by zenrpc; DO NOT EDIT.
+
+package testdata
+
+import (
+	"context"
+
+	"github.com/semrush/zenrpc/v2/client"
+
+	"github.com/semrush/zenrpc/v2/testdata/model"
+)
+
+// AdminPhoneBookClient is a typed JSON-RPC 2.0 client for AdminPhoneBook.
+type AdminPhoneBookClient struct {
+	client    *client.Client
+	namespace string
+}
+
+// NewAdminPhoneBookClient returns new typed client for AdminPhoneBook registered with given namespace.
+func NewAdminPhoneBookClient(c *client.Client, namespace string) *AdminPhoneBookClient {
+	return &AdminPhoneBookClient{client: c, namespace: namespace}
+}
+
+// method returns method name with namespace.
+func (zc *AdminPhoneBookClient) method(name string) string {
+	if zc.namespace == "" {
+		return name
+	}
+
+	return zc.namespace + "." + name
+}
+
+// Purge removes deleted persons from phonebook and returns their number.
+func (zc *AdminPhoneBookClient) Purge(ctx context.Context) (int, error) {
+	var zres int
+	err := zc.client.Call(ctx, zc.method(RPC.AdminPhoneBook.Purge), nil, &zres)
+	return zres, err
+}
+
+// Echo returns string with admin prefix, it shadows PhoneBook.Echo.
+func (zc *AdminPhoneBookClient) Echo(ctx context.Context, str string) (string, error) {
+	var zres string
+	err := zc.client.Call(ctx, zc.method(RPC.AdminPhoneBook.Echo), map[string]interface{}{"str": str}, &zres)
+	return zres, err
+}
+
+// Get returns all people from DB.
+func (zc *AdminPhoneBookClient) Get(ctx context.Context, search PersonSearch, page *int, count *int) ([]*Person, error) {
+	var zres []*Person
+	err := zc.client.Call(ctx, zc.method(RPC.AdminPhoneBook.Get), map[string]interface{}{"search": search, "page": page, "count": count}, &zres)
+	return zres, err
+}
+
+// ValidateSearch returns given search as result.
+func (zc *AdminPhoneBookClient) ValidateSearch(ctx context.Context, search *PersonSearch) (*PersonSearch, error) {
+	var zres *PersonSearch
+	err := zc.client.Call(ctx, zc.method(RPC.AdminPhoneBook.ValidateSearch), map[string]interface{}{"search": search}, &zres)
+	return zres, err
+}
+
+// ById returns Person from DB.
+func (zc *AdminPhoneBookClient) ById(ctx context.Context, id PersonID) (*Person, error) {
+	var zres *Person
+	err := zc.client.Call(ctx, zc.method(RPC.AdminPhoneBook.ById), map[string]interface{}{"id": id}, &zres)
+	return zres, err
+}
+
+// Delete marks person as deleted.
+func (zc *AdminPhoneBookClient) Delete(ctx context.Context, id uint64) (bool, error) {
+	var zres bool
+	err := zc.client.Call(ctx, zc.method(RPC.AdminPhoneBook.Delete), map[string]interface{}{"id": id}, &zres)
+	return zres, err
+}
+
+// Removes deletes person from DB.
+func (zc *AdminPhoneBookClient) Remove(ctx context.Context, id uint64) (bool, error) {
+	var zres bool
+	err := zc.client.Call(ctx, zc.method(RPC.AdminPhoneBook.Remove), map[string]interface{}{"id": id}, &zres)
+	return zres, err
+}
+
+// Save saves person to DB.
+func (zc *AdminPhoneBookClient) Save(ctx context.Context, p Person, replace *bool) (uint64, error) {
+	var zres uint64
+	err := zc.client.Call(ctx, zc.method(RPC.AdminPhoneBook.Save), map[string]interface{}{"p": p, "replace": replace}, &zres)
+	return zres, err
+}
+
+// ArithServiceClient is a typed JSON-RPC 2.0 client for ArithService.
+type ArithServiceClient struct {
+	client    *client.Client
+	namespace string
+}
+
+// NewArithServiceClient returns new typed client for ArithService registered with given namespace.
+func NewArithServiceClient(c *client.Client, namespace string) *ArithServiceClient {
+	return &ArithServiceClient{client: c, namespace: namespace}
+}
+
+// method returns method name with namespace.
+func (zc *ArithServiceClient) method(name string) string {
+	if zc.namespace == "" {
+		return name
+	}
+
+	return zc.namespace + "." + name
+}
+
+// Sum sums two digits and returns error with error code as result and IP from context.
+func (zc *ArithServiceClient) Sum(ctx context.Context, a int, b int) (bool, error) {
+	var zres bool
+	err := zc.client.Call(ctx, zc.method(RPC.ArithService.Sum), map[string]interface{}{"a": a, "b": b}, &zres)
+	return zres, err
+}
+
+// Positive calls ArithService.Positive method.
+func (zc *ArithServiceClient) Positive(ctx context.Context) (bool, error) {
+	var zres bool
+	err := zc.client.Call(ctx, zc.method(RPC.ArithService.Positive), nil, &zres)
+	return zres, err
+}
+
+// DoSomething calls ArithService.DoSomething method.
+func (zc *ArithServiceClient) DoSomething(ctx context.Context) error {
+	return zc.client.Call(ctx, zc.method(RPC.ArithService.DoSomething), nil, nil)
+}
+
+// GetPoints calls ArithService.GetPoints method.
+func (zc *ArithServiceClient) GetPoints(ctx context.Context) ([]model.Point, error) {
+	var zres []model.Point
+	err := zc.client.Call(ctx, zc.method(RPC.ArithService.GetPoints), nil, &zres)
+	return zres, err
+}
+
+// DoSomethingWithPoint calls ArithService.DoSomethingWithPoint method.
+func (zc *ArithServiceClient) DoSomethingWithPoint(ctx context.Context, p model.Point) (model.Point, error) {
+	var zres model.Point
+	err := zc.client.Call(ctx, zc.method(RPC.ArithService.DoSomethingWithPoint), map[string]interface{}{"p": p}, &zres)
+	return zres, err
+}
+
+// Multiply multiples two digits and returns result.
+func (zc *ArithServiceClient) Multiply(ctx context.Context, a int, b int) (int, error) {
+	var zres int
+	err := zc.client.Call(ctx, zc.method(RPC.ArithService.Multiply), map[string]interface{}{"a": a, "b": b}, &zres)
+	return zres, err
+}
+
+// CheckError throws error is isErr true.
+func (zc *ArithServiceClient) CheckError(ctx context.Context, isErr bool) error {
+	return zc.client.Call(ctx, zc.method(RPC.ArithService.CheckError), map[string]interface{}{"isErr": isErr}, nil)
+}
+
+// CheckError throws zenrpc error is isErr true.
+func (zc *ArithServiceClient) CheckZenRPCError(ctx context.Context, isErr bool) error {
+	return zc.client.Call(ctx, zc.method(RPC.ArithService.CheckZenRPCError), map[string]interface{}{"isErr": isErr}, nil)
+}
+
+// Divide divides two numbers.
+func (zc *ArithServiceClient) Divide(ctx context.Context, a int, b int) (*Quotient, error) {
+	var zres *Quotient
+	err := zc.client.Call(ctx, zc.method(RPC.ArithService.Divide), map[string]interface{}{"a": a, "b": b}, &zres)
+	return zres, err
+}
+
+// Pow returns x**y, the base-x exponential of y. If Exp is not set then default value is 2.
+func (zc *ArithServiceClient) Pow(ctx context.Context, base float64, exp *float64) (float64, error) {
+	var zres float64
+	err := zc.client.Call(ctx, zc.method(RPC.ArithService.Pow), map[string]interface{}{"base": base, "exp": exp}, &zres)
+	return zres, err
+}
+
+// PI returns math.Pi.
+func (zc *ArithServiceClient) Pi(ctx context.Context) (float64, error) {
+	var zres float64
+	err := zc.client.Call(ctx, zc.method(RPC.ArithService.Pi), nil, &zres)
+	return zres, err
+}
+
+// SumArray returns sum all items from array
+func (zc *ArithServiceClient) SumArray(ctx context.Context, array *[]float64) (float64, error) {
+	var zres float64
+	err := zc.client.Call(ctx, zc.method(RPC.ArithService.SumArray), map[string]interface{}{"array": array}, &zres)
+	return zres, err
+}
+
+// CatalogueServiceClient is a typed JSON-RPC 2.0 client for CatalogueService.
+type CatalogueServiceClient struct {
+	client    *client.Client
+	namespace string
+}
+
+// NewCatalogueServiceClient returns new typed client for CatalogueService registered with given namespace.
+func NewCatalogueServiceClient(c *client.Client, namespace string) *CatalogueServiceClient {
+	return &CatalogueServiceClient{client: c, namespace: namespace}
+}
+
+// method returns method name with namespace.
+func (zc *CatalogueServiceClient) method(name string) string {
+	if zc.namespace == "" {
+		return name
+	}
+
+	return zc.namespace + "." + name
+}
+
+// First calls CatalogueService.First method.
+func (zc *CatalogueServiceClient) First(ctx context.Context, groups []Group) (bool, error) {
+	var zres bool
+	err := zc.client.Call(ctx, zc.method(RPC.CatalogueService.First), map[string]interface{}{"groups": groups}, &zres)
+	return zres, err
+}
+
+// Second calls CatalogueService.Second method.
+func (zc *CatalogueServiceClient) Second(ctx context.Context, campaigns []Campaign) (bool, error) {
+	var zres bool
+	err := zc.client.Call(ctx, zc.method(RPC.CatalogueService.Second), map[string]interface{}{"campaigns": campaigns}, &zres)
+	return zres, err
+}
+
+// Third calls CatalogueService.Third method.
+func (zc *CatalogueServiceClient) Third(ctx context.Context) (Campaign, error) {
+	var zres Campaign
+	err := zc.client.Call(ctx, zc.method(RPC.CatalogueService.Third), nil, &zres)
+	return zres, err
+}
+
+// Fourth returns group for even id and campaign for odd id.
+func (zc *CatalogueServiceClient) Fourth(ctx context.Context, id int) (interface{}, error) {
+	var zres interface{}
+	err := zc.client.Call(ctx, zc.method(RPC.CatalogueService.Fourth), map[string]interface{}{"id": id}, &zres)
+	return zres, err
+}
+
+// Fifth returns groups with given ids matching filter.
+func (zc *CatalogueServiceClient) Fifth(ctx context.Context, filter struct {
+	Title string `json:"title"`
+	Type  *GroupType
+}, ids ...int) ([]Group, error) {
+	var zres []Group
+	err := zc.client.Call(ctx, zc.method(RPC.CatalogueService.Fifth), map[string]interface{}{"filter": filter, "ids": ids}, &zres)
+	return zres, err
+}
+
+// Stats returns total and average number of groups in campaigns.
+func (zc *CatalogueServiceClient) Stats(ctx context.Context, campaigns []Campaign) (int, float64, error) {
+	var zres struct {
+		Groups int     `json:"groups"`
+		Avg    float64 `json:"avg"`
+	}
+	err := zc.client.Call(ctx, zc.method(RPC.CatalogueService.Stats), map[string]interface{}{"campaigns": campaigns}, &zres)
+	return zres.Groups, zres.Avg, err
+}
+
+// ContactsClient is a typed JSON-RPC 2.0 client for Contacts.
+type ContactsClient struct {
+	client    *client.Client
+	namespace string
+}
+
+// NewContactsClient returns new typed client for Contacts registered with given namespace.
+func NewContactsClient(c *client.Client, namespace string) *ContactsClient {
+	return &ContactsClient{client: c, namespace: namespace}
+}
+
+// method returns method name with namespace.
+func (zc *ContactsClient) method(name string) string {
+	if zc.namespace == "" {
+		return name
+	}
+
+	return zc.namespace + "." + name
+}
+
+// ContactsClient implements Contacts interface.
+var _ Contacts = (*ContactsClient)(nil)
+
+// Find returns persons with first name starting with prefix.
+func (zc *ContactsClient) Find(ctx context.Context, prefix string) ([]Person, error) {
+	var zres []Person
+	err := zc.client.Call(ctx, zc.method(RPC.Contacts.Find), map[string]interface{}{"prefix": prefix}, &zres)
+	return zres, err
+}
+
+// Count returns number of persons.
+func (zc *ContactsClient) Count(ctx context.Context) (int, error) {
+	var zres int
+	err := zc.client.Call(ctx, zc.method(RPC.Contacts.Count), nil, &zres)
+	return zres, err
+}
+
+// PhoneBookClient is a typed JSON-RPC 2.0 client for PhoneBook.
+type PhoneBookClient struct {
+	client    *client.Client
+	namespace string
+}
+
+// NewPhoneBookClient returns new typed client for PhoneBook registered with given namespace.
+func NewPhoneBookClient(c *client.Client, namespace string) *PhoneBookClient {
+	return &PhoneBookClient{client: c, namespace: namespace}
+}
+
+// method returns method name with namespace.
+func (zc *PhoneBookClient) method(name string) string {
+	if zc.namespace == "" {
+		return name
+	}
+
+	return zc.namespace + "." + name
+}
+
+// Get returns all people from DB.
+func (zc *PhoneBookClient) Get(ctx context.Context, search PersonSearch, page *int, count *int) ([]*Person, error) {
+	var zres []*Person
+	err := zc.client.Call(ctx, zc.method(RPC.PhoneBook.Get), map[string]interface{}{"search": search, "page": page, "count": count}, &zres)
+	return zres, err
+}
+
+// ValidateSearch returns given search as result.
+func (zc *PhoneBookClient) ValidateSearch(ctx context.Context, search *PersonSearch) (*PersonSearch, error) {
+	var zres *PersonSearch
+	err := zc.client.Call(ctx, zc.method(RPC.PhoneBook.ValidateSearch), map[string]interface{}{"search": search}, &zres)
+	return zres, err
+}
+
+// ById returns Person from DB.
+func (zc *PhoneBookClient) ById(ctx context.Context, id PersonID) (*Person, error) {
+	var zres *Person
+	err := zc.client.Call(ctx, zc.method(RPC.PhoneBook.ById), map[string]interface{}{"id": id}, &zres)
+	return zres, err
+}
+
+// Delete marks person as deleted.
+func (zc *PhoneBookClient) Delete(ctx context.Context, id uint64) (bool, error) {
+	var zres bool
+	err := zc.client.Call(ctx, zc.method(RPC.PhoneBook.Delete), map[string]interface{}{"id": id}, &zres)
+	return zres, err
+}
+
+// Removes deletes person from DB.
+func (zc *PhoneBookClient) Remove(ctx context.Context, id uint64) (bool, error) {
+	var zres bool
+	err := zc.client.Call(ctx, zc.method(RPC.PhoneBook.Remove), map[string]interface{}{"id": id}, &zres)
+	return zres, err
+}
+
+// Save saves person to DB.
+func (zc *PhoneBookClient) Save(ctx context.Context, p Person, replace *bool) (uint64, error) {
+	var zres uint64
+	err := zc.client.Call(ctx, zc.method(RPC.PhoneBook.Save), map[string]interface{}{"p": p, "replace": replace}, &zres)
+	return zres, err
+}
+
+// Prints message
+func (zc *PhoneBookClient) Echo(ctx context.Context, str string) (string, error) {
+	var zres string
+	err := zc.client.Call(ctx, zc.method(RPC.PhoneBook.Echo), map[string]interface{}{"type": str}, &zres)
+	return zres, err
+}
+
+// PrintServiceClient is a typed JSON-RPC 2.0 client for PrintService.
+type PrintServiceClient struct {
+	client    *client.Client
+	namespace string
+}
+
+// NewPrintServiceClient returns new typed client for PrintService registered with given namespace.
+func NewPrintServiceClient(c *client.Client, namespace string) *PrintServiceClient {
+	return &PrintServiceClient{client: c, namespace: namespace}
+}
+
+// method returns method name with namespace.
+func (zc *PrintServiceClient) method(name string) string {
+	if zc.namespace == "" {
+		return name
+	}
+
+	return zc.namespace + "." + name
+}
+
+// PrintRequiredDefault calls PrintService.PrintRequiredDefault method.
+func (zc *PrintServiceClient) PrintRequiredDefault(ctx context.Context, s string) (string, error) {
+	var zres string
+	err := zc.client.Call(ctx, zc.method(RPC.PrintService.PrintRequiredDefault), map[string]interface{}{"s": s}, &zres)
+	return zres, err
+}
+
+// PrintOptionalWithDefault calls PrintService.PrintOptionalWithDefault method.
+func (zc *PrintServiceClient) PrintOptionalWithDefault(ctx context.Context, s *string) (string, error) {
+	var zres string
+	err := zc.client.Call(ctx, zc.method(RPC.PrintService.PrintOptionalWithDefault), map[string]interface{}{"s": s}, &zres)
+	return zres, err
+}
+
+// PrintRequired calls PrintService.PrintRequired method.
+func (zc *PrintServiceClient) PrintRequired(ctx context.Context, s string) (string, error) {
+	var zres string
+	err := zc.client.Call(ctx, zc.method(RPC.PrintService.PrintRequired), map[string]interface{}{"s": s}, &zres)
+	return zres, err
+}
+
+// PrintOptional calls PrintService.PrintOptional method.
+func (zc *PrintServiceClient) PrintOptional(ctx context.Context, s *string) (string, error) {
+	var zres string
+	err := zc.client.Call(ctx, zc.method(RPC.PrintService.PrintOptional), map[string]interface{}{"s": s}, &zres)
+	return zres, err
+}
@@ -13,11 +13,23 @@ import (
 )
 
 var RPC = struct {
+	AdminPhoneBook   struct{ Purge, Echo, Get, ValidateSearch, ById, Delete, Remove, Save string }
 	ArithService     struct{ Sum, Positive, DoSomething, GetPoints, DoSomethingWithPoint, Multiply, CheckError, CheckZenRPCError, Divide, Pow, Pi, SumArray string }
-	CatalogueService struct{ First, Second, Third string }
+	CatalogueService struct{ First, Second, Third, Fourth, Fifth, Stats string }
+	Contacts         struct{ Find, Count string }
 	PhoneBook        struct{ Get, ValidateSearch, ById, Delete, Remove, Save, Echo string }
 	PrintService     struct{ PrintRequiredDefault, PrintOptionalWithDefault, PrintRequired, PrintOptional string }
 }{
+	AdminPhoneBook: struct{ Purge, Echo, Get, ValidateSearch, ById, Delete, Remove, Save string }{
+		Purge:          "purge",
+		Echo:           "echo",
+		Get:            "get",
+		ValidateSearch: "validatesearch",
+		ById:           "byid",
+		Delete:         "delete",
+		Remove:         "remove",
+		Save:           "save",
+	},
 	ArithService: struct{ Sum, Positive, DoSomething, GetPoints, DoSomethingWithPoint, Multiply, CheckError, CheckZenRPCError, Divide, Pow, Pi, SumArray string }{
 		Sum:                  "sum",
 		Positive:             "positive",
@@ -32,10 +44,17 @@ var RPC = struct {
 		Pi:                   "pi",
 		SumArray:             "sumarray",
 	},
-	CatalogueService: struct{ First, Second, Third string }{
+	CatalogueService: struct{ First, Second, Third, Fourth, Fifth, Stats string }{
 		First:  "first",
 		Second: "second",
 		Third:  "third",
+		Fourth: "fourth",
+		Fifth:  "fifth",
+		Stats:  "stats",
+	},
+	Contacts: struct{ Find, Count string }{
+		Find:  "find",
+		Count: "count",
 	},
 	PhoneBook: struct{ Get, ValidateSearch, ById, Delete, Remove, Save, Echo string }{
 		Get:            "get",
@@ -54,293 +73,299 @@ var RPC = struct {
 	},
 }
 
-func (ArithService) SMD() smd.ServiceInfo {
+func (AdminPhoneBook) SMD() smd.ServiceInfo {
 	return smd.ServiceInfo{
 		Description: ``,
 		Methods: map[string]smd.Service{
-			"Sum": {
-				Description: `Sum sums two digits and returns error with error code as result and IP from context.`,
-				Parameters: []smd.JSONSchema{
-					{
-						Name:        "a",
-						Optional:    false,
-						Description: ``,
-						Type:        smd.Integer,
-					},
-					{
-						Name:        "b",
-						Optional:    false,
-						Description: ``,
-						Type:        smd.Integer,
-					},
-				},
-				Returns: smd.JSONSchema{
-					Description: ``,
-					Optional:    false,
-					Type:        smd.Boolean,
-				},
-			},
-			"Positive": {
-				Description: ``,
-				Parameters:  []smd.JSONSchema{},
-				Returns: smd.JSONSchema{
-					Description: ``,
-					Optional:    false,
-					Type:        smd.Boolean,
-				},
-			},
-			"DoSomething": {
-				Description: ``,
-				Parameters:  []smd.JSONSchema{},
-			},
-			"GetPoints": {
-				Description: ``,
+			"Purge": {
+				Description: `Purge removes deleted persons from phonebook and returns their number.`,
 				Parameters:  []smd.JSONSchema{},
 				Returns: smd.JSONSchema{
 					Description: ``,
 					Optional:    false,
-					Type:        smd.Array,
-					Items: map[string]string{
-						"$ref": "#/definitions/model.Point",
-					},
-					Definitions: map[string]smd.Definition{
-						"model.Point": {
-							Type: "object",
-							Properties: map[string]smd.Property{
-								"X": {
-									Description: `coordinate`,
-									Type:        smd.Integer,
-								},
-								"Y": {
-									Description: `coordinate`,
-									Type:        smd.Integer,
-								},
-								"ConnectedObject": {
-									Description: ``,
-									Ref:         "#/definitions/objects.AbstractObject",
-									Type:        smd.Object,
-								},
-							},
-						},
-						"objects.AbstractObject": {
-							Type:       "object",
-							Properties: map[string]smd.Property{},
-						},
-					},
+					Type:        smd.Integer,
 				},
 			},
-			"DoSomethingWithPoint": {
-				Description: ``,
+			"Echo": {
+				Description: `Echo returns string with admin prefix, it shadows PhoneBook.Echo.`,
 				Parameters: []smd.JSONSchema{
 					{
-						Name:        "p",
+						Name:        "str",
 						Optional:    false,
 						Description: ``,
-						Type:        smd.Object,
-						Properties: map[string]smd.Property{
-							"X": {
-								Description: `coordinate`,
-								Type:        smd.Integer,
-							},
-							"Y": {
-								Description: `coordinate`,
-								Type:        smd.Integer,
-							},
-							"ConnectedObject": {
-								Description: ``,
-								Ref:         "#/definitions/objects.AbstractObject",
-								Type:        smd.Object,
-							},
-						},
-						Definitions: map[string]smd.Definition{
-							"objects.AbstractObject": {
-								Type:       "object",
-								Properties: map[string]smd.Property{},
-							},
-						},
+						Type:        smd.String,
 					},
 				},
 				Returns: smd.JSONSchema{
 					Description: ``,
 					Optional:    false,
-					Type:        smd.Object,
-					Properties: map[string]smd.Property{
-						"X": {
-							Description: `coordinate`,
-							Type:        smd.Integer,
-						},
-						"Y": {
-							Description: `coordinate`,
-							Type:        smd.Integer,
-						},
-						"ConnectedObject": {
-							Description: ``,
-							Ref:         "#/definitions/objects.AbstractObject",
-							Type:        smd.Object,
-						},
-					},
-					Definitions: map[string]smd.Definition{
-						"objects.AbstractObject": {
-							Type:       "object",
-							Properties: map[string]smd.Property{},
-						},
-					},
+					Type:        smd.String,
 				},
 			},
-			"Multiply": {
-				Description: `Multiply multiples two digits and returns result.`,
+			"Get": {
+				Description: `Get returns all people from DB.`,
 				Parameters: []smd.JSONSchema{
 					{
-						Name:        "a",
+						Name:        "search",
 						Optional:    false,
 						Description: ``,
+						Type:        smd.Object,
+						Ref:         "#/definitions/testdata.PersonSearch",
+					},
+					{
+						Name:        "page",
+						Optional:    true,
+						Default:     smd.Default[int](0),
+						Description: `current page`,
 						Type:        smd.Integer,
+						Constraints: smd.Constraints{
+							Minimum: smd.Number(0),
+						},
 					},
 					{
-						Name:        "b",
-						Optional:    false,
-						Description: ``,
+						Name:        "count",
+						Optional:    true,
+						Default:     smd.Default[int](50),
+						Description: `page size`,
 						Type:        smd.Integer,
+						Constraints: smd.Constraints{
+							Minimum: smd.Number(1),
+							Maximum: smd.Number(100),
+						},
 					},
 				},
 				Returns: smd.JSONSchema{
 					Description: ``,
 					Optional:    false,
-					Type:        smd.Integer,
+					Type:        smd.Array,
+					Items: &smd.Property{
+						Type: smd.Object,
+						Ref:  "#/definitions/testdata.Person",
+					},
 				},
 			},
-			"CheckError": {
-				Description: `CheckError throws error is isErr true.`,
+			"ValidateSearch": {
+				Description: `ValidateSearch returns given search as result.`,
 				Parameters: []smd.JSONSchema{
 					{
-						Name:        "isErr",
-						Optional:    false,
-						Description: ``,
-						Type:        smd.Boolean,
+						Name:        "search",
+						Optional:    true,
+						Description: `search object`,
+						Type:        smd.Object,
+						Ref:         "#/definitions/testdata.PersonSearch",
 					},
 				},
-				Errors: map[int]string{
-					500: "test error",
+				Returns: smd.JSONSchema{
+					Description: ``,
+					Optional:    true,
+					Type:        smd.Object,
+					Ref:         "#/definitions/testdata.PersonSearch",
 				},
 			},
-			"CheckZenRPCError": {
-				Description: `CheckError throws zenrpc error is isErr true.`,
+			"ById": {
+				Description: `ById returns Person from DB.`,
 				Parameters: []smd.JSONSchema{
 					{
-						Name:        "isErr",
+						Name:        "id",
 						Optional:    false,
-						Description: ``,
-						Type:        smd.Boolean,
+						Description: `person id`,
+						Type:        smd.Integer,
 					},
 				},
+				Returns: smd.JSONSchema{
+					Description: ``,
+					Optional:    true,
+					Type:        smd.Object,
+					Ref:         "#/definitions/testdata.Person",
+				},
 				Errors: map[int]string{
-					500: "test error",
+					404: "person was not found",
 				},
 			},
-			"Divide": {
-				Description: `Divide divides two numbers.`,
+			"Delete": {
+				Description: `Delete marks person as deleted.`,
 				Parameters: []smd.JSONSchema{
 					{
-						Name:        "a",
+						Name:        "id",
 						Optional:    false,
-						Description: ``,
+						Description: `person id`,
 						Type:        smd.Integer,
 					},
+				},
+				Returns: smd.JSONSchema{
+					Description: ``,
+					Optional:    false,
+					Type:        smd.Boolean,
+				},
+			},
+			"Remove": {
+				Description: `Removes deletes person from DB.`,
+				Parameters: []smd.JSONSchema{
 					{
-						Name:        "b",
+						Name:        "id",
 						Optional:    false,
-						Description: `the b`,
+						Description: `person id`,
 						Type:        smd.Integer,
 					},
 				},
 				Returns: smd.JSONSchema{
-					Description: ``,
-					Optional:    true,
-					Type:        smd.Object,
-					Properties: map[string]smd.Property{
-						"Quo": {
-							Description: `Quo docs`,
-							Type:        smd.Integer,
-						},
-						"rem": {
-							Description: `Rem docs`,
-							Type:        smd.Integer,
-						},
-					},
-				},
-				Errors: map[int]string{
-					401:    "we do not serve 1",
-					-32603: "divide by zero",
+					Description: `operation result`,
+					Optional:    false,
+					Type:        smd.Boolean,
 				},
 			},
-			"Pow": {
-				Description: `Pow returns x**y, the base-x exponential of y. If Exp is not set then default value is 2.`,
+			"Save": {
+				Description: `Save saves person to DB.`,
 				Parameters: []smd.JSONSchema{
 					{
-						Name:        "base",
+						Name:        "p",
 						Optional:    false,
 						Description: ``,
-						Type:        smd.Float,
+						Type:        smd.Object,
+						Ref:         "#/definitions/testdata.Person",
 					},
 					{
-						Name:        "exp",
+						Name:        "replace",
 						Optional:    true,
-						Description: `exponent could be empty`,
-						Type:        smd.Float,
+						Default:     smd.Default[bool](false),
+						Description: `update person if exist`,
+						Type:        smd.Boolean,
 					},
 				},
 				Returns: smd.JSONSchema{
 					Description: ``,
 					Optional:    false,
-					Type:        smd.Float,
+					Type:        smd.Integer,
+				},
+				Errors: map[int]string{
+					400: "invalid request",
+					401: "use replace=true",
 				},
 			},
-			"Pi": {
-				Description: `PI returns math.Pi.`,
-				Parameters:  []smd.JSONSchema{},
-				Returns: smd.JSONSchema{
-					Description: ``,
-					Optional:    false,
-					Type:        smd.Float,
+		},
+		Definitions: map[string]smd.Definition{
+			"testdata.PersonSearch": {
+				Type: smd.Object,
+				Properties: map[string]smd.Property{
+					"ByName": {
+						Description: `ByName is filter for searching person by first name or last name.`,
+						Nullable:    true,
+						Type:        smd.String,
+					},
+					"ByType": {
+						Description: ``,
+						Nullable:    true,
+						Type:        smd.String,
+						Constraints: smd.Constraints{
+							Enum: []interface{}{"mobile", "work"},
+						},
+					},
+					"ByPhone": {
+						Description: ``,
+						Type:        smd.String,
+						Constraints: smd.Constraints{
+							MaxLength: 20,
+							Pattern:   "^\\+?[0-9-]*$",
+						},
+					},
+					"ByAddress": {
+						Description: ``,
+						Nullable:    true,
+						Type:        smd.Object,
+						Ref:         "#/definitions/testdata.Address",
+					},
 				},
+				Required: []string{"ByPhone"},
 			},
-			"SumArray": {
-				Description: `SumArray returns sum all items from array`,
-				Parameters: []smd.JSONSchema{
-					{
-						Name:        "array",
-						Optional:    true,
+			"testdata.Address": {
+				Type: smd.Object,
+				Properties: map[string]smd.Property{
+					"Street": {
+						Description: ``,
+						Type:        smd.String,
+					},
+					"City": {
+						Description: ``,
+						Type:        smd.String,
+					},
+				},
+				Required: []string{"Street", "City"},
+			},
+			"testdata.Person": {
+				Type: smd.Object,
+				Properties: map[string]smd.Property{
+					"ID": {
+						Description: `ID is Unique Identifier for person`,
+						Type:        smd.Integer,
+					},
+					"FirstName": {
+						Description: ``,
+						Type:        smd.String,
+					},
+					"LastName": {
+						Description: ``,
+						Type:        smd.String,
+					},
+					"Phone": {
+						Description: `Phone is main phone`,
+						Type:        smd.String,
+					},
+					"WorkPhone": {
+						Description: ``,
+						Nullable:    true,
+						Type:        smd.String,
+					},
+					"Mobile": {
 						Description: ``,
 						Type:        smd.Array,
-						Items: map[string]string{
-							"type": smd.Float,
+						Items: &smd.Property{
+							Type: smd.String,
 						},
 					},
+					"Deleted": {
+						Description: `Deleted is flag for`,
+						Type:        smd.Boolean,
+					},
+					"Addresses": {
+						Description: `Addresses Could be nil or len() == 0.`,
+						Type:        smd.Array,
+						Items: &smd.Property{
+							Type: smd.Object,
+							Ref:  "#/definitions/testdata.Address",
+						},
+					},
+					"address": {
+						Description: ``,
+						Nullable:    true,
+						Type:        smd.Object,
+						Ref:         "#/definitions/testdata.Address",
+					},
 				},
-				Returns: smd.JSONSchema{
-					Description: ``,
-					Optional:    false,
-					Type:        smd.Float,
-				},
+				Required: []string{"ID", "FirstName", "LastName", "Phone", "Mobile", "Deleted", "Addresses"},
 			},
 		},
 	}
 }
 
+// smdAdminPhoneBook is used for params constraints validation.
+var smdAdminPhoneBook = AdminPhoneBook{}.SMD()
+
 // Invoke is as generated code from zenrpc cmd
-func (s ArithService) Invoke(ctx context.Context, method string, params json.RawMessage) zenrpc.Response {
+func (s AdminPhoneBook) Invoke(ctx context.Context, method string, params json.RawMessage) zenrpc.Response {
 	resp := zenrpc.Response{}
 	var err error
 
 	switch method {
-	case RPC.ArithService.Sum:
+	case RPC.AdminPhoneBook.Purge:
+		resp.Set(s.Purge())
+
+	case RPC.AdminPhoneBook.Echo:
 		var args = struct {
-			A int `json:"a"`
-			B int `json:"b"`
+			Str string `json:"str"`
 		}{}
 
 		if zenrpc.IsArray(params) {
-			if params, err = zenrpc.ConvertToObject([]string{"a", "b"}, params); err != nil {
+			if params, err = zenrpc.ConvertToObject([]string{"str"}, params); err != nil {
 				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
 			}
 		}
@@ -351,24 +376,510 @@ func (s ArithService) Invoke(ctx context.Context, method string, params json.Raw
 			}
 		}
 
-		resp.Set(s.Sum(ctx, args.A, args.B))
+		resp.Set(s.Echo(args.Str))
 
-	case RPC.ArithService.Positive:
-		resp.Set(s.Positive())
+	case RPC.AdminPhoneBook.Get:
+		if errs := smdAdminPhoneBook.Methods["Get"].ValidateConstraints(params, smdAdminPhoneBook.Definitions); len(errs) > 0 {
+			return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", errs)
+		}
 
-	case RPC.ArithService.DoSomething:
-		s.DoSomething()
+		var args = struct {
+			Search PersonSearch `json:"search"`
+			Page   *int         `json:"page"`
+			Count  *int         `json:"count"`
+		}{}
+
+		if zenrpc.IsArray(params) {
+			if params, err = zenrpc.ConvertToObject([]string{"search", "page", "count"}, params); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		if len(params) > 0 {
+			if err := json.Unmarshal(params, &args); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		//zenrpc:count=50 min=1 max=100 page size
+		if args.Count == nil {
+			var v int = 50
+			args.Count = &v
+		}
+
+		//zenrpc:page=0 min=0 current page
+		if args.Page == nil {
+			var v int = 0
+			args.Page = &v
+		}
+
+		resp.Set(s.Get(args.Search, args.Page, args.Count))
+
+	case RPC.AdminPhoneBook.ValidateSearch:
+		if errs := smdAdminPhoneBook.Methods["ValidateSearch"].ValidateConstraints(params, smdAdminPhoneBook.Definitions); len(errs) > 0 {
+			return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", errs)
+		}
+
+		var args = struct {
+			Search *PersonSearch `json:"search"`
+		}{}
+
+		if zenrpc.IsArray(params) {
+			if params, err = zenrpc.ConvertToObject([]string{"search"}, params); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		if len(params) > 0 {
+			if err := json.Unmarshal(params, &args); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		resp.Set(s.ValidateSearch(args.Search))
+
+	case RPC.AdminPhoneBook.ById:
+		var args = struct {
+			Id PersonID `json:"id"`
+		}{}
+
+		if zenrpc.IsArray(params) {
+			if params, err = zenrpc.ConvertToObject([]string{"id"}, params); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		if len(params) > 0 {
+			if err := json.Unmarshal(params, &args); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		resp.Set(s.ById(args.Id))
+
+	case RPC.AdminPhoneBook.Delete:
+		var args = struct {
+			Id uint64 `json:"id"`
+		}{}
+
+		if zenrpc.IsArray(params) {
+			if params, err = zenrpc.ConvertToObject([]string{"id"}, params); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		if len(params) > 0 {
+			if err := json.Unmarshal(params, &args); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		resp.Set(s.Delete(args.Id))
+
+	case RPC.AdminPhoneBook.Remove:
+		var args = struct {
+			Id uint64 `json:"id"`
+		}{}
+
+		if zenrpc.IsArray(params) {
+			if params, err = zenrpc.ConvertToObject([]string{"id"}, params); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		if len(params) > 0 {
+			if err := json.Unmarshal(params, &args); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		resp.Set(s.Remove(args.Id))
+
+	case RPC.AdminPhoneBook.Save:
+		var args = struct {
+			P       Person `json:"p"`
+			Replace *bool  `json:"replace"`
+		}{}
+
+		if zenrpc.IsArray(params) {
+			if params, err = zenrpc.ConvertToObject([]string{"p", "replace"}, params); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		if len(params) > 0 {
+			if err := json.Unmarshal(params, &args); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		//zenrpc:replace=false update person if exist
+		if args.Replace == nil {
+			var v bool = false
+			args.Replace = &v
+		}
+
+		resp.Set(s.Save(args.P, args.Replace))
+
+	default:
+		resp = zenrpc.NewResponseError(nil, zenrpc.MethodNotFound, "", nil)
+	}
+
+	return resp
+}
+
+func (ArithService) SMD() smd.ServiceInfo {
+	return smd.ServiceInfo{
+		Description: ``,
+		Methods: map[string]smd.Service{
+			"Sum": {
+				Description: `Sum sums two digits and returns error with error code as result and IP from context.`,
+				Parameters: []smd.JSONSchema{
+					{
+						Name:        "a",
+						Optional:    false,
+						Description: ``,
+						Type:        smd.Integer,
+					},
+					{
+						Name:        "b",
+						Optional:    false,
+						Description: ``,
+						Type:        smd.Integer,
+					},
+				},
+				Returns: smd.JSONSchema{
+					Description: ``,
+					Optional:    false,
+					Type:        smd.Boolean,
+				},
+			},
+			"Positive": {
+				Description: ``,
+				Parameters:  []smd.JSONSchema{},
+				Returns: smd.JSONSchema{
+					Description: ``,
+					Optional:    false,
+					Type:        smd.Boolean,
+				},
+			},
+			"DoSomething": {
+				Description: ``,
+				Parameters:  []smd.JSONSchema{},
+				Returns:     smd.JSONSchema{Type: smd.Null},
+			},
+			"GetPoints": {
+				Description: ``,
+				Parameters:  []smd.JSONSchema{},
+				Returns: smd.JSONSchema{
+					Description: ``,
+					Optional:    false,
+					Type:        smd.Array,
+					Items: &smd.Property{
+						Type: smd.Object,
+						Ref:  "#/definitions/model.Point",
+					},
+				},
+			},
+			"DoSomethingWithPoint": {
+				Description: ``,
+				Parameters: []smd.JSONSchema{
+					{
+						Name:        "p",
+						Optional:    false,
+						Description: ``,
+						Type:        smd.Object,
+						Ref:         "#/definitions/model.Point",
+					},
+				},
+				Returns: smd.JSONSchema{
+					Description: ``,
+					Optional:    false,
+					Type:        smd.Object,
+					Ref:         "#/definitions/model.Point",
+				},
+			},
+			"Multiply": {
+				Description: `Multiply multiples two digits and returns result.`,
+				Parameters: []smd.JSONSchema{
+					{
+						Name:        "a",
+						Optional:    false,
+						Description: ``,
+						Type:        smd.Integer,
+					},
+					{
+						Name:        "b",
+						Optional:    false,
+						Description: ``,
+						Type:        smd.Integer,
+					},
+				},
+				Returns: smd.JSONSchema{
+					Description: ``,
+					Optional:    false,
+					Type:        smd.Integer,
+				},
+			},
+			"CheckError": {
+				Description: `CheckError throws error is isErr true.`,
+				Parameters: []smd.JSONSchema{
+					{
+						Name:        "isErr",
+						Optional:    false,
+						Description: ``,
+						Type:        smd.Boolean,
+					},
+				},
+				Returns: smd.JSONSchema{Type: smd.Null},
+				Errors: map[int]string{
+					500: "test error",
+				},
+			},
+			"CheckZenRPCError": {
+				Description: `CheckError throws zenrpc error is isErr true.`,
+				Parameters: []smd.JSONSchema{
+					{
+						Name:        "isErr",
+						Optional:    false,
+						Description: ``,
+						Type:        smd.Boolean,
+					},
+				},
+				Returns: smd.JSONSchema{Type: smd.Null},
+				Errors: map[int]string{
+					500: "test error",
+				},
+			},
+			"Divide": {
+				Description: `Divide divides two numbers.`,
+				Parameters: []smd.JSONSchema{
+					{
+						Name:        "a",
+						Optional:    false,
+						Description: ``,
+						Type:        smd.Integer,
+					},
+					{
+						Name:        "b",
+						Optional:    false,
+						Description: `the b`,
+						Type:        smd.Integer,
+					},
+				},
+				Returns: smd.JSONSchema{
+					Description: ``,
+					Optional:    true,
+					Type:        smd.Object,
+					Ref:         "#/definitions/testdata.Quotient",
+				},
+				Errors: map[int]string{
+					401:    "we do not serve 1",
+					-32603: "divide by zero",
+				},
+			},
+			"Pow": {
+				Description: `Pow returns x**y, the base-x exponential of y. If Exp is not set then default value is 2.`,
+				Parameters: []smd.JSONSchema{
+					{
+						Name:        "base",
+						Optional:    false,
+						Description: ``,
+						Type:        smd.Float,
+					},
+					{
+						Name:        "exp",
+						Optional:    true,
+						Default:     smd.Default[float64](2),
+						Description: `exponent could be empty`,
+						Type:        smd.Float,
+					},
+				},
+				Returns: smd.JSONSchema{
+					Description: ``,
+					Optional:    false,
+					Type:        smd.Float,
+				},
+			},
+			"Pi": {
+				Description: `PI returns math.Pi.`,
+				Parameters:  []smd.JSONSchema{},
+				Returns: smd.JSONSchema{
+					Description: ``,
+					Optional:    false,
+					Type:        smd.Float,
+				},
+			},
+			"SumArray": {
+				Description: `SumArray returns sum all items from array`,
+				Parameters: []smd.JSONSchema{
+					{
+						Name:        "array",
+						Optional:    true,
+						Default:     smd.Default[[]float64]([]float64{1, 2, 4}),
+						Description: ``,
+						Type:        smd.Array,
+						Items: &smd.Property{
+							Type: smd.Float,
+						},
+					},
+				},
+				Returns: smd.JSONSchema{
+					Description: ``,
+					Optional:    false,
+					Type:        smd.Float,
+				},
+			},
+		},
+		Definitions: map[string]smd.Definition{
+			"model.Point": {
+				Type: smd.Object,
+				Properties: map[string]smd.Property{
+					"Name": {
+						Description: ``,
+						Type:        smd.String,
+					},
+					"SomeField": {
+						Description: ``,
+						Type:        smd.String,
+					},
+					"Measure": {
+						Description: ``,
+						Type:        smd.Float,
+					},
+					"X": {
+						Description: `coordinate`,
+						Type:        smd.Integer,
+					},
+					"Y": {
+						Description: `coordinate`,
+						Type:        smd.Integer,
+					},
+					"ConnectedObject": {
+						Description: ``,
+						Type:        smd.Object,
+						Ref:         "#/definitions/objects.AbstractObject",
+					},
+				},
+				Required: []string{"Name", "SomeField", "Measure", "X", "Y", "ConnectedObject"},
+			},
+			"objects.AbstractObject": {
+				Type: smd.Object,
+				Properties: map[string]smd.Property{
+					"Name": {
+						Description: ``,
+						Type:        smd.String,
+					},
+					"SomeField": {
+						Description: ``,
+						Type:        smd.String,
+					},
+					"Measure": {
+						Description: ``,
+						Type:        smd.Float,
+					},
+				},
+				Required: []string{"Name", "SomeField", "Measure"},
+			},
+			"testdata.Quotient": {
+				Type: smd.Object,
+				Properties: map[string]smd.Property{
+					"Quo": {
+						Description: `Quo docs`,
+						Type:        smd.Integer,
+					},
+					"rem": {
+						Description: `Rem docs`,
+						Type:        smd.Integer,
+					},
+				},
+				Required: []string{"Quo", "rem"},
+			},
+		},
+	}
+}
+
+// Invoke is as generated code from zenrpc cmd
+func (s ArithService) Invoke(ctx context.Context, method string, params json.RawMessage) zenrpc.Response {
+	resp := zenrpc.Response{}
+	var err error
+
+	switch method {
+	case RPC.ArithService.Sum:
+		var args = struct {
+			A int `json:"a"`
+			B int `json:"b"`
+		}{}
+
+		if zenrpc.IsArray(params) {
+			if params, err = zenrpc.ConvertToObject([]string{"a", "b"}, params); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		if len(params) > 0 {
+			if err := json.Unmarshal(params, &args); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		resp.Set(s.Sum(ctx, args.A, args.B))
+
+	case RPC.ArithService.Positive:
+		resp.Set(s.Positive())
+
+	case RPC.ArithService.DoSomething:
+		s.DoSomething()
+
+	case RPC.ArithService.GetPoints:
+		resp.Set(s.GetPoints())
+
+	case RPC.ArithService.DoSomethingWithPoint:
+		var args = struct {
+			P model.Point `json:"p"`
+		}{}
+
+		if zenrpc.IsArray(params) {
+			if params, err = zenrpc.ConvertToObject([]string{"p"}, params); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		if len(params) > 0 {
+			if err := json.Unmarshal(params, &args); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		resp.Set(s.DoSomethingWithPoint(args.P))
+
+	case RPC.ArithService.Multiply:
+		var args = struct {
+			A int `json:"a"`
+			B int `json:"b"`
+		}{}
+
+		if zenrpc.IsArray(params) {
+			if params, err = zenrpc.ConvertToObject([]string{"a", "b"}, params); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
 
-	case RPC.ArithService.GetPoints:
-		resp.Set(s.GetPoints())
+		if len(params) > 0 {
+			if err := json.Unmarshal(params, &args); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
 
-	case RPC.ArithService.DoSomethingWithPoint:
+		resp.Set(s.Multiply(args.A, args.B))
+
+	case RPC.ArithService.CheckError:
 		var args = struct {
-			P model.Point `json:"p"`
+			IsErr bool `json:"isErr"`
 		}{}
 
 		if zenrpc.IsArray(params) {
-			if params, err = zenrpc.ConvertToObject([]string{"p"}, params); err != nil {
+			if params, err = zenrpc.ConvertToObject([]string{"isErr"}, params); err != nil {
 				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
 			}
 		}
@@ -379,9 +890,28 @@ func (s ArithService) Invoke(ctx context.Context, method string, params json.Raw
 			}
 		}
 
-		resp.Set(s.DoSomethingWithPoint(args.P))
+		resp.Set(s.CheckError(args.IsErr))
 
-	case RPC.ArithService.Multiply:
+	case RPC.ArithService.CheckZenRPCError:
+		var args = struct {
+			IsErr bool `json:"isErr"`
+		}{}
+
+		if zenrpc.IsArray(params) {
+			if params, err = zenrpc.ConvertToObject([]string{"isErr"}, params); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		if len(params) > 0 {
+			if err := json.Unmarshal(params, &args); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		resp.Set(s.CheckZenRPCError(args.IsErr))
+
+	case RPC.ArithService.Divide:
 		var args = struct {
 			A int `json:"a"`
 			B int `json:"b"`
@@ -399,15 +929,346 @@ func (s ArithService) Invoke(ctx context.Context, method string, params json.Raw
 			}
 		}
 
-		resp.Set(s.Multiply(args.A, args.B))
+		resp.Set(s.Divide(args.A, args.B))
+
+	case RPC.ArithService.Pow:
+		var args = struct {
+			Base float64  `json:"base"`
+			Exp  *float64 `json:"exp"`
+		}{}
+
+		if zenrpc.IsArray(params) {
+			if params, err = zenrpc.ConvertToObject([]string{"base", "exp"}, params); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		if len(params) > 0 {
+			if err := json.Unmarshal(params, &args); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		//zenrpc:exp=2 	exponent could be empty
+		if args.Exp == nil {
+			var v float64 = 2
+			args.Exp = &v
+		}
+
+		resp.Set(s.Pow(args.Base, args.Exp))
+
+	case RPC.ArithService.Pi:
+		resp.Set(s.Pi())
+
+	case RPC.ArithService.SumArray:
+		var args = struct {
+			Array *[]float64 `json:"array"`
+		}{}
+
+		if zenrpc.IsArray(params) {
+			if params, err = zenrpc.ConvertToObject([]string{"array"}, params); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		if len(params) > 0 {
+			if err := json.Unmarshal(params, &args); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		//zenrpc:array=[]float64{1,2,4}
+		if args.Array == nil {
+			var v []float64 = []float64{1, 2, 4}
+			args.Array = &v
+		}
+
+		resp.Set(s.SumArray(args.Array))
+
+	default:
+		resp = zenrpc.NewResponseError(nil, zenrpc.MethodNotFound, "", nil)
+	}
+
+	return resp
+}
+
+func (CatalogueService) SMD() smd.ServiceInfo {
+	return smd.ServiceInfo{
+		Description: ``,
+		Methods: map[string]smd.Service{
+			"First": {
+				Description: ``,
+				Parameters: []smd.JSONSchema{
+					{
+						Name:        "groups",
+						Optional:    false,
+						Description: ``,
+						Type:        smd.Array,
+						Items: &smd.Property{
+							Type: smd.Object,
+							Ref:  "#/definitions/testdata.Group",
+						},
+					},
+				},
+				Returns: smd.JSONSchema{
+					Description: ``,
+					Optional:    false,
+					Type:        smd.Boolean,
+				},
+			},
+			"Second": {
+				Description: ``,
+				Parameters: []smd.JSONSchema{
+					{
+						Name:        "campaigns",
+						Optional:    false,
+						Description: ``,
+						Type:        smd.Array,
+						Items: &smd.Property{
+							Type: smd.Object,
+							Ref:  "#/definitions/testdata.Campaign",
+						},
+					},
+				},
+				Returns: smd.JSONSchema{
+					Description: ``,
+					Optional:    false,
+					Type:        smd.Boolean,
+				},
+			},
+			"Third": {
+				Description: ``,
+				Parameters:  []smd.JSONSchema{},
+				Returns: smd.JSONSchema{
+					Description: ``,
+					Optional:    false,
+					Type:        smd.Object,
+					Ref:         "#/definitions/testdata.Campaign",
+				},
+			},
+			"Fourth": {
+				Description: `Fourth returns group for even id and campaign for odd id.`,
+				Parameters: []smd.JSONSchema{
+					{
+						Name:        "id",
+						Optional:    false,
+						Description: ``,
+						Type:        smd.Integer,
+					},
+				},
+				Returns: smd.JSONSchema{
+					Description: `group or campaign`,
+					Optional:    false,
+					Type:        smd.Any,
+					OneOf: []smd.Property{
+						{
+							Type: smd.Object,
+							Ref:  "#/definitions/testdata.Group",
+						},
+						{
+							Type: smd.Object,
+							Ref:  "#/definitions/testdata.Campaign",
+						},
+					},
+				},
+			},
+			"Fifth": {
+				Description: `Fifth returns groups with given ids matching filter.`,
+				Parameters: []smd.JSONSchema{
+					{
+						Name:        "filter",
+						Optional:    false,
+						Description: ``,
+						Type:        smd.Object,
+						Ref:         "#/definitions/testdata.CatalogueService_Fifth_filter",
+					},
+					{
+						Name:        "ids",
+						Optional:    true,
+						Description: ``,
+						Type:        smd.Array,
+						Items: &smd.Property{
+							Type: smd.Integer,
+						},
+					},
+				},
+				Returns: smd.JSONSchema{
+					Description: ``,
+					Optional:    false,
+					Type:        smd.Array,
+					Items: &smd.Property{
+						Type: smd.Object,
+						Ref:  "#/definitions/testdata.Group",
+					},
+				},
+			},
+			"Stats": {
+				Description: `Stats returns total and average number of groups in campaigns.`,
+				Parameters: []smd.JSONSchema{
+					{
+						Name:        "campaigns",
+						Optional:    false,
+						Description: ``,
+						Type:        smd.Array,
+						Items: &smd.Property{
+							Type: smd.Object,
+							Ref:  "#/definitions/testdata.Campaign",
+						},
+					},
+				},
+				Returns: smd.JSONSchema{
+					Description: ``,
+					Optional:    false,
+					Type:        smd.Object,
+					Ref:         "#/definitions/testdata.CatalogueService_StatsResult",
+				},
+			},
+		},
+		Definitions: map[string]smd.Definition{
+			"testdata.Group": {
+				Type: smd.Object,
+				Properties: map[string]smd.Property{
+					"id": {
+						Description: ``,
+						Type:        smd.Integer,
+					},
+					"title": {
+						Description: ``,
+						Type:        smd.String,
+					},
+					"nodes": {
+						Description: ``,
+						Type:        smd.Array,
+						Items: &smd.Property{
+							Type: smd.Object,
+							Ref:  "#/definitions/testdata.Group",
+						},
+					},
+					"group": {
+						Description: ``,
+						Type:        smd.Array,
+						Items: &smd.Property{
+							Type: smd.Object,
+							Ref:  "#/definitions/testdata.Group",
+						},
+					},
+					"child": {
+						Description: ``,
+						Nullable:    true,
+						Type:        smd.Object,
+						Ref:         "#/definitions/testdata.Group",
+					},
+					"sub": {
+						Description: ``,
+						Type:        smd.Object,
+						Ref:         "#/definitions/testdata.SubGroup",
+					},
+					"type": {
+						Description: `GroupType is a type of group.`,
+						Type:        smd.String,
+						Constraints: smd.Constraints{
+							Enum: []interface{}{"default", "hidden"},
+						},
+					},
+				},
+				Required: []string{"id", "title", "nodes", "group", "sub", "type"},
+			},
+			"testdata.SubGroup": {
+				Type: smd.Object,
+				Properties: map[string]smd.Property{
+					"id": {
+						Description: ``,
+						Type:        smd.Integer,
+					},
+					"title": {
+						Description: ``,
+						Type:        smd.String,
+					},
+					"nodes": {
+						Description: ``,
+						Type:        smd.Array,
+						Items: &smd.Property{
+							Type: smd.Object,
+							Ref:  "#/definitions/testdata.Group",
+						},
+					},
+				},
+				Required: []string{"id", "title", "nodes"},
+			},
+			"testdata.Campaign": {
+				Type: smd.Object,
+				Properties: map[string]smd.Property{
+					"id": {
+						Description: ``,
+						Type:        smd.Integer,
+					},
+					"group": {
+						Description: ``,
+						Type:        smd.Array,
+						Items: &smd.Property{
+							Type: smd.Object,
+							Ref:  "#/definitions/testdata.Group",
+						},
+					},
+					"status": {
+						Description: `CampaignStatus is a status of campaign.`,
+						Type:        smd.Integer,
+						Constraints: smd.Constraints{
+							Enum: []interface{}{1, 2, 3},
+						},
+					},
+				},
+				Required: []string{"id", "group", "status"},
+			},
+			"testdata.CatalogueService_Fifth_filter": {
+				Type: smd.Object,
+				Properties: map[string]smd.Property{
+					"title": {
+						Description: ``,
+						Type:        smd.String,
+					},
+					"Type": {
+						Description: `GroupType is a type of group.`,
+						Nullable:    true,
+						Type:        smd.String,
+						Constraints: smd.Constraints{
+							Enum: []interface{}{"default", "hidden"},
+						},
+					},
+				},
+				Required: []string{"title"},
+			},
+			"testdata.CatalogueService_StatsResult": {
+				Type: smd.Object,
+				Properties: map[string]smd.Property{
+					"groups": {
+						Description: ``,
+						Type:        smd.Integer,
+					},
+					"avg": {
+						Description: ``,
+						Type:        smd.Float,
+					},
+				},
+				Required: []string{"groups", "avg"},
+			},
+		},
+	}
+}
+
+// Invoke is as generated code from zenrpc cmd
+func (s CatalogueService) Invoke(ctx context.Context, method string, params json.RawMessage) zenrpc.Response {
+	resp := zenrpc.Response{}
+	var err error
 
-	case RPC.ArithService.CheckError:
+	switch method {
+	case RPC.CatalogueService.First:
 		var args = struct {
-			IsErr bool `json:"isErr"`
+			Groups []Group `json:"groups"`
 		}{}
 
 		if zenrpc.IsArray(params) {
-			if params, err = zenrpc.ConvertToObject([]string{"isErr"}, params); err != nil {
+			if params, err = zenrpc.ConvertToObject([]string{"groups"}, params); err != nil {
 				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
 			}
 		}
@@ -418,15 +1279,15 @@ func (s ArithService) Invoke(ctx context.Context, method string, params json.Raw
 			}
 		}
 
-		resp.Set(s.CheckError(args.IsErr))
+		resp.Set(s.First(args.Groups))
 
-	case RPC.ArithService.CheckZenRPCError:
+	case RPC.CatalogueService.Second:
 		var args = struct {
-			IsErr bool `json:"isErr"`
+			Campaigns []Campaign `json:"campaigns"`
 		}{}
 
 		if zenrpc.IsArray(params) {
-			if params, err = zenrpc.ConvertToObject([]string{"isErr"}, params); err != nil {
+			if params, err = zenrpc.ConvertToObject([]string{"campaigns"}, params); err != nil {
 				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
 			}
 		}
@@ -437,16 +1298,18 @@ func (s ArithService) Invoke(ctx context.Context, method string, params json.Raw
 			}
 		}
 
-		resp.Set(s.CheckZenRPCError(args.IsErr))
+		resp.Set(s.Second(args.Campaigns))
 
-	case RPC.ArithService.Divide:
+	case RPC.CatalogueService.Third:
+		resp.Set(s.Third())
+
+	case RPC.CatalogueService.Fourth:
 		var args = struct {
-			A int `json:"a"`
-			B int `json:"b"`
+			Id int `json:"id"`
 		}{}
 
 		if zenrpc.IsArray(params) {
-			if params, err = zenrpc.ConvertToObject([]string{"a", "b"}, params); err != nil {
+			if params, err = zenrpc.ConvertToObject([]string{"id"}, params); err != nil {
 				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
 			}
 		}
@@ -457,16 +1320,19 @@ func (s ArithService) Invoke(ctx context.Context, method string, params json.Raw
 			}
 		}
 
-		resp.Set(s.Divide(args.A, args.B))
+		resp.Set(s.Fourth(args.Id))
 
-	case RPC.ArithService.Pow:
+	case RPC.CatalogueService.Fifth:
 		var args = struct {
-			Base float64  `json:"base"`
-			Exp  *float64 `json:"exp"`
+			Filter struct {
+				Title string `json:"title"`
+				Type  *GroupType
+			} `json:"filter"`
+			Ids []int `json:"ids"`
 		}{}
 
 		if zenrpc.IsArray(params) {
-			if params, err = zenrpc.ConvertToObject([]string{"base", "exp"}, params); err != nil {
+			if params, err = zenrpc.ConvertToObject([]string{"filter", "ids"}, params); err != nil {
 				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
 			}
 		}
@@ -477,24 +1343,15 @@ func (s ArithService) Invoke(ctx context.Context, method string, params json.Raw
 			}
 		}
 
-		//zenrpc:exp=2 	exponent could be empty
-		if args.Exp == nil {
-			var v float64 = 2
-			args.Exp = &v
-		}
-
-		resp.Set(s.Pow(args.Base, args.Exp))
-
-	case RPC.ArithService.Pi:
-		resp.Set(s.Pi())
+		resp.Set(s.Fifth(args.Filter, args.Ids...))
 
-	case RPC.ArithService.SumArray:
+	case RPC.CatalogueService.Stats:
 		var args = struct {
-			Array *[]float64 `json:"array"`
+			Campaigns []Campaign `json:"campaigns"`
 		}{}
 
 		if zenrpc.IsArray(params) {
-			if params, err = zenrpc.ConvertToObject([]string{"array"}, params); err != nil {
+			if params, err = zenrpc.ConvertToObject([]string{"campaigns"}, params); err != nil {
 				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
 			}
 		}
@@ -505,13 +1362,11 @@ func (s ArithService) Invoke(ctx context.Context, method string, params json.Raw
 			}
 		}
 
-		//zenrpc:array=[]float64{1,2,4}
-		if args.Array == nil {
-			var v []float64 = []float64{1, 2, 4}
-			args.Array = &v
-		}
-
-		resp.Set(s.SumArray(args.Array))
+		zr0, zr1, zr2 := s.Stats(args.Campaigns)
+		resp.Set(struct {
+			Groups interface{} `json:"groups"`
+			Avg    interface{} `json:"avg"`
+		}{zr0, zr1}, zr2)
 
 	default:
 		resp = zenrpc.NewResponseError(nil, zenrpc.MethodNotFound, "", nil)
@@ -520,278 +1375,134 @@ func (s ArithService) Invoke(ctx context.Context, method string, params json.Raw
 	return resp
 }
 
-func (CatalogueService) SMD() smd.ServiceInfo {
+// ContactsInvoker is zenrpc.Invoker adapter for Contacts implementation.
+type ContactsInvoker struct {
+	Contacts
+}
+
+// NewContactsInvoker returns zenrpc.Invoker for given Contacts implementation.
+func NewContactsInvoker(impl Contacts) ContactsInvoker {
+	return ContactsInvoker{impl}
+}
+
+func (ContactsInvoker) SMD() smd.ServiceInfo {
 	return smd.ServiceInfo{
-		Description: ``,
+		Description: `Contacts is contract of contacts service, it is implemented by ContactList.`,
 		Methods: map[string]smd.Service{
-			"First": {
-				Description: ``,
+			"Find": {
+				Description: `Find returns persons with first name starting with prefix.`,
 				Parameters: []smd.JSONSchema{
 					{
-						Name:        "groups",
+						Name:        "prefix",
 						Optional:    false,
-						Description: ``,
-						Type:        smd.Array,
-						Items: map[string]string{
-							"$ref": "#/definitions/Group",
-						},
-						Definitions: map[string]smd.Definition{
-							"Group": {
-								Type: "object",
-								Properties: map[string]smd.Property{
-									"id": {
-										Description: ``,
-										Type:        smd.Integer,
-									},
-									"title": {
-										Description: ``,
-										Type:        smd.String,
-									},
-									"nodes": {
-										Description: ``,
-										Type:        smd.Array,
-										Items: map[string]string{
-											"$ref": "#/definitions/Group",
-										},
-									},
-									"group": {
-										Description: ``,
-										Type:        smd.Array,
-										Items: map[string]string{
-											"$ref": "#/definitions/Group",
-										},
-									},
-									"child": {
-										Description: ``,
-										Ref:         "#/definitions/Group",
-										Type:        smd.Object,
-									},
-									"sub": {
-										Description: ``,
-										Ref:         "#/definitions/SubGroup",
-										Type:        smd.Object,
-									},
-								},
-							},
-							"SubGroup": {
-								Type: "object",
-								Properties: map[string]smd.Property{
-									"id": {
-										Description: ``,
-										Type:        smd.Integer,
-									},
-									"title": {
-										Description: ``,
-										Type:        smd.String,
-									},
-								},
-							},
-						},
+						Description: `name prefix`,
+						Type:        smd.String,
 					},
 				},
 				Returns: smd.JSONSchema{
 					Description: ``,
 					Optional:    false,
-					Type:        smd.Boolean,
-				},
-			},
-			"Second": {
-				Description: ``,
-				Parameters: []smd.JSONSchema{
-					{
-						Name:        "campaigns",
-						Optional:    false,
-						Description: ``,
-						Type:        smd.Array,
-						Items: map[string]string{
-							"$ref": "#/definitions/Campaign",
-						},
-						Definitions: map[string]smd.Definition{
-							"Campaign": {
-								Type: "object",
-								Properties: map[string]smd.Property{
-									"id": {
-										Description: ``,
-										Type:        smd.Integer,
-									},
-									"group": {
-										Description: ``,
-										Type:        smd.Array,
-										Items: map[string]string{
-											"$ref": "#/definitions/Group",
-										},
-									},
-								},
-							},
-							"Group": {
-								Type: "object",
-								Properties: map[string]smd.Property{
-									"id": {
-										Description: ``,
-										Type:        smd.Integer,
-									},
-									"title": {
-										Description: ``,
-										Type:        smd.String,
-									},
-									"nodes": {
-										Description: ``,
-										Type:        smd.Array,
-										Items: map[string]string{
-											"$ref": "#/definitions/Group",
-										},
-									},
-									"group": {
-										Description: ``,
-										Type:        smd.Array,
-										Items: map[string]string{
-											"$ref": "#/definitions/Group",
-										},
-									},
-									"child": {
-										Description: ``,
-										Ref:         "#/definitions/Group",
-										Type:        smd.Object,
-									},
-									"sub": {
-										Description: ``,
-										Ref:         "#/definitions/SubGroup",
-										Type:        smd.Object,
-									},
-								},
-							},
-							"SubGroup": {
-								Type: "object",
-								Properties: map[string]smd.Property{
-									"id": {
-										Description: ``,
-										Type:        smd.Integer,
-									},
-									"title": {
-										Description: ``,
-										Type:        smd.String,
-									},
-								},
-							},
-						},
+					Type:        smd.Array,
+					Items: &smd.Property{
+						Type: smd.Object,
+						Ref:  "#/definitions/testdata.Person",
 					},
 				},
-				Returns: smd.JSONSchema{
-					Description: ``,
-					Optional:    false,
-					Type:        smd.Boolean,
-				},
 			},
-			"Third": {
-				Description: ``,
+			"Count": {
+				Description: `Count returns number of persons.`,
 				Parameters:  []smd.JSONSchema{},
 				Returns: smd.JSONSchema{
 					Description: ``,
 					Optional:    false,
-					Type:        smd.Object,
-					Properties: map[string]smd.Property{
-						"id": {
-							Description: ``,
-							Type:        smd.Integer,
-						},
-						"group": {
-							Description: ``,
-							Type:        smd.Array,
-							Items: map[string]string{
-								"$ref": "#/definitions/Group",
-							},
-						},
+					Type:        smd.Integer,
+				},
+			},
+		},
+		Definitions: map[string]smd.Definition{
+			"testdata.Person": {
+				Type: smd.Object,
+				Properties: map[string]smd.Property{
+					"ID": {
+						Description: `ID is Unique Identifier for person`,
+						Type:        smd.Integer,
+					},
+					"FirstName": {
+						Description: ``,
+						Type:        smd.String,
+					},
+					"LastName": {
+						Description: ``,
+						Type:        smd.String,
+					},
+					"Phone": {
+						Description: `Phone is main phone`,
+						Type:        smd.String,
+					},
+					"WorkPhone": {
+						Description: ``,
+						Nullable:    true,
+						Type:        smd.String,
 					},
-					Definitions: map[string]smd.Definition{
-						"Group": {
-							Type: "object",
-							Properties: map[string]smd.Property{
-								"id": {
-									Description: ``,
-									Type:        smd.Integer,
-								},
-								"title": {
-									Description: ``,
-									Type:        smd.String,
-								},
-								"nodes": {
-									Description: ``,
-									Type:        smd.Array,
-									Items: map[string]string{
-										"$ref": "#/definitions/Group",
-									},
-								},
-								"group": {
-									Description: ``,
-									Type:        smd.Array,
-									Items: map[string]string{
-										"$ref": "#/definitions/Group",
-									},
-								},
-								"child": {
-									Description: ``,
-									Ref:         "#/definitions/Group",
-									Type:        smd.Object,
-								},
-								"sub": {
-									Description: ``,
-									Ref:         "#/definitions/SubGroup",
-									Type:        smd.Object,
-								},
-							},
+					"Mobile": {
+						Description: ``,
+						Type:        smd.Array,
+						Items: &smd.Property{
+							Type: smd.String,
 						},
-						"SubGroup": {
-							Type: "object",
-							Properties: map[string]smd.Property{
-								"id": {
-									Description: ``,
-									Type:        smd.Integer,
-								},
-								"title": {
-									Description: ``,
-									Type:        smd.String,
-								},
-							},
+					},
+					"Deleted": {
+						Description: `Deleted is flag for`,
+						Type:        smd.Boolean,
+					},
+					"Addresses": {
+						Description: `Addresses Could be nil or len() == 0.`,
+						Type:        smd.Array,
+						Items: &smd.Property{
+							Type: smd.Object,
+							Ref:  "#/definitions/testdata.Address",
 						},
 					},
+					"address": {
+						Description: ``,
+						Nullable:    true,
+						Type:        smd.Object,
+						Ref:         "#/definitions/testdata.Address",
+					},
+				},
+				Required: []string{"ID", "FirstName", "LastName", "Phone", "Mobile", "Deleted", "Addresses"},
+			},
+			"testdata.Address": {
+				Type: smd.Object,
+				Properties: map[string]smd.Property{
+					"Street": {
+						Description: ``,
+						Type:        smd.String,
+					},
+					"City": {
+						Description: ``,
+						Type:        smd.String,
+					},
 				},
+				Required: []string{"Street", "City"},
 			},
 		},
 	}
 }
 
 // Invoke is as generated code from zenrpc cmd
-func (s CatalogueService) Invoke(ctx context.Context, method string, params json.RawMessage) zenrpc.Response {
+func (s ContactsInvoker) Invoke(ctx context.Context, method string, params json.RawMessage) zenrpc.Response {
 	resp := zenrpc.Response{}
 	var err error
 
 	switch method {
-	case RPC.CatalogueService.First:
-		var args = struct {
-			Groups []Group `json:"groups"`
-		}{}
-
-		if zenrpc.IsArray(params) {
-			if params, err = zenrpc.ConvertToObject([]string{"groups"}, params); err != nil {
-				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
-			}
-		}
-
-		if len(params) > 0 {
-			if err := json.Unmarshal(params, &args); err != nil {
-				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
-			}
-		}
-
-		resp.Set(s.First(args.Groups))
-
-	case RPC.CatalogueService.Second:
+	case RPC.Contacts.Find:
 		var args = struct {
-			Campaigns []Campaign `json:"campaigns"`
+			Prefix string `json:"prefix"`
 		}{}
 
 		if zenrpc.IsArray(params) {
-			if params, err = zenrpc.ConvertToObject([]string{"campaigns"}, params); err != nil {
+			if params, err = zenrpc.ConvertToObject([]string{"prefix"}, params); err != nil {
 				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
 			}
 		}
@@ -802,10 +1513,10 @@ func (s CatalogueService) Invoke(ctx context.Context, method string, params json
 			}
 		}
 
-		resp.Set(s.Second(args.Campaigns))
+		resp.Set(s.Find(ctx, args.Prefix))
 
-	case RPC.CatalogueService.Third:
-		resp.Set(s.Third())
+	case RPC.Contacts.Count:
+		resp.Set(s.Count(ctx))
 
 	default:
 		resp = zenrpc.NewResponseError(nil, zenrpc.MethodNotFound, "", nil)
@@ -826,123 +1537,37 @@ func (PhoneBook) SMD() smd.ServiceInfo {
 						Optional:    false,
 						Description: ``,
 						Type:        smd.Object,
-						Properties: map[string]smd.Property{
-							"ByName": {
-								Description: `ByName is filter for searching person by first name or last name.`,
-								Type:        smd.String,
-							},
-							"ByType": {
-								Description: ``,
-								Type:        smd.String,
-							},
-							"ByPhone": {
-								Description: ``,
-								Type:        smd.String,
-							},
-							"ByAddress": {
-								Description: ``,
-								Ref:         "#/definitions/Address",
-								Type:        smd.Object,
-							},
-						},
-						Definitions: map[string]smd.Definition{
-							"Address": {
-								Type: "object",
-								Properties: map[string]smd.Property{
-									"Street": {
-										Description: ``,
-										Type:        smd.String,
-									},
-									"City": {
-										Description: ``,
-										Type:        smd.String,
-									},
-								},
-							},
-						},
+						Ref:         "#/definitions/testdata.PersonSearch",
 					},
 					{
 						Name:        "page",
 						Optional:    true,
+						Default:     smd.Default[int](0),
 						Description: `current page`,
 						Type:        smd.Integer,
+						Constraints: smd.Constraints{
+							Minimum: smd.Number(0),
+						},
 					},
 					{
 						Name:        "count",
 						Optional:    true,
+						Default:     smd.Default[int](50),
 						Description: `page size`,
 						Type:        smd.Integer,
+						Constraints: smd.Constraints{
+							Minimum: smd.Number(1),
+							Maximum: smd.Number(100),
+						},
 					},
 				},
 				Returns: smd.JSONSchema{
 					Description: ``,
 					Optional:    false,
 					Type:        smd.Array,
-					Items: map[string]string{
-						"$ref": "#/definitions/Person",
-					},
-					Definitions: map[string]smd.Definition{
-						"Person": {
-							Type: "object",
-							Properties: map[string]smd.Property{
-								"ID": {
-									Description: `ID is Unique Identifier for person`,
-									Type:        smd.Integer,
-								},
-								"FirstName": {
-									Description: ``,
-									Type:        smd.String,
-								},
-								"LastName": {
-									Description: ``,
-									Type:        smd.String,
-								},
-								"Phone": {
-									Description: `Phone is main phone`,
-									Type:        smd.String,
-								},
-								"WorkPhone": {
-									Description: ``,
-									Type:        smd.String,
-								},
-								"Mobile": {
-									Description: ``,
-									Type:        smd.Array,
-									Items: map[string]string{
-										"type": smd.String,
-									},
-								},
-								"Deleted": {
-									Description: `Deleted is flag for`,
-									Type:        smd.Boolean,
-								},
-								"Addresses": {
-									Description: `Addresses Could be nil or len() == 0.`,
-									Type:        smd.Array,
-									Items: map[string]string{
-										"$ref": "#/definitions/Address",
-									},
-								},
-								"address": {
-									Description: ``,
-									Ref:         "#/definitions/Address",
-									Type:        smd.Object,
-								},
-							},
-						},
-						"Address": {
-							Type: "object",
-							Properties: map[string]smd.Property{
-								"Street": {
-									Description: ``,
-									Type:        smd.String,
-								},
-								"City": {
-									Description: ``,
-									Type:        smd.String,
-								},
-							},
-						},
+					Items: &smd.Property{
+						Type: smd.Object,
+						Ref:  "#/definitions/testdata.Person",
 					},
 				},
 			},
@@ -954,80 +1579,14 @@ func (PhoneBook) SMD() smd.ServiceInfo {
 						Optional:    true,
 						Description: `search object`,
 						Type:        smd.Object,
-						Properties: map[string]smd.Property{
-							"ByName": {
-								Description: `ByName is filter for searching person by first name or last name.`,
-								Type:        smd.String,
-							},
-							"ByType": {
-								Description: ``,
-								Type:        smd.String,
-							},
-							"ByPhone": {
-								Description: ``,
-								Type:        smd.String,
-							},
-							"ByAddress": {
-								Description: ``,
-								Ref:         "#/definitions/Address",
-								Type:        smd.Object,
-							},
-						},
-						Definitions: map[string]smd.Definition{
-							"Address": {
-								Type: "object",
-								Properties: map[string]smd.Property{
-									"Street": {
-										Description: ``,
-										Type:        smd.String,
-									},
-									"City": {
-										Description: ``,
-										Type:        smd.String,
-									},
-								},
-							},
-						},
+						Ref:         "#/definitions/testdata.PersonSearch",
 					},
 				},
 				Returns: smd.JSONSchema{
 					Description: ``,
 					Optional:    true,
 					Type:        smd.Object,
-					Properties: map[string]smd.Property{
-						"ByName": {
-							Description: `ByName is filter for searching person by first name or last name.`,
-							Type:        smd.String,
-						},
-						"ByType": {
-							Description: ``,
-							Type:        smd.String,
-						},
-						"ByPhone": {
-							Description: ``,
-							Type:        smd.String,
-						},
-						"ByAddress": {
-							Description: ``,
-							Ref:         "#/definitions/Address",
-							Type:        smd.Object,
-						},
-					},
-					Definitions: map[string]smd.Definition{
-						"Address": {
-							Type: "object",
-							Properties: map[string]smd.Property{
-								"Street": {
-									Description: ``,
-									Type:        smd.String,
-								},
-								"City": {
-									Description: ``,
-									Type:        smd.String,
-								},
-							},
-						},
-					},
+					Ref:         "#/definitions/testdata.PersonSearch",
 				},
 			},
 			"ById": {
@@ -1044,66 +1603,7 @@ func (PhoneBook) SMD() smd.ServiceInfo {
 					Description: ``,
 					Optional:    true,
 					Type:        smd.Object,
-					Properties: map[string]smd.Property{
-						"ID": {
-							Description: `ID is Unique Identifier for person`,
-							Type:        smd.Integer,
-						},
-						"FirstName": {
-							Description: ``,
-							Type:        smd.String,
-						},
-						"LastName": {
-							Description: ``,
-							Type:        smd.String,
-						},
-						"Phone": {
-							Description: `Phone is main phone`,
-							Type:        smd.String,
-						},
-						"WorkPhone": {
-							Description: ``,
-							Type:        smd.String,
-						},
-						"Mobile": {
-							Description: ``,
-							Type:        smd.Array,
-							Items: map[string]string{
-								"type": smd.String,
-							},
-						},
-						"Deleted": {
-							Description: `Deleted is flag for`,
-							Type:        smd.Boolean,
-						},
-						"Addresses": {
-							Description: `Addresses Could be nil or len() == 0.`,
-							Type:        smd.Array,
-							Items: map[string]string{
-								"$ref": "#/definitions/Address",
-							},
-						},
-						"address": {
-							Description: ``,
-							Ref:         "#/definitions/Address",
-							Type:        smd.Object,
-						},
-					},
-					Definitions: map[string]smd.Definition{
-						"Address": {
-							Type: "object",
-							Properties: map[string]smd.Property{
-								"Street": {
-									Description: ``,
-									Type:        smd.String,
-								},
-								"City": {
-									Description: ``,
-									Type:        smd.String,
-								},
-							},
-						},
-					},
+					Ref:         "#/definitions/testdata.Person",
 				},
 				Errors: map[int]string{
 					404: "person was not found",
@@ -1149,70 +1649,12 @@ func (PhoneBook) SMD() smd.ServiceInfo {
 						Optional:    false,
 						Description: ``,
 						Type:        smd.Object,
-						Properties: map[string]smd.Property{
-							"ID": {
-								Description: `ID is Unique Identifier for person`,
-								Type:        smd.Integer,
-							},
-							"FirstName": {
-								Description: ``,
-								Type:        smd.String,
-							},
-							"LastName": {
-								Description: ``,
-								Type:        smd.String,
-							},
-							"Phone": {
-								Description: `Phone is main phone`,
-								Type:        smd.String,
-							},
-							"WorkPhone": {
-								Description: ``,
-								Type:        smd.String,
-							},
-							"Mobile": {
-								Description: ``,
-								Type:        smd.Array,
-								Items: map[string]string{
-									"type": smd.String,
-								},
-							},
-							"Deleted": {
-								Description: `Deleted is flag for`,
-								Type:        smd.Boolean,
-							},
-							"Addresses": {
-								Description: `Addresses Could be nil or len() == 0.`,
-								Type:        smd.Array,
-								Items: map[string]string{
-									"$ref": "#/definitions/Address",
-								},
-							},
-							"address": {
-								Description: ``,
-								Ref:         "#/definitions/Address",
-								Type:        smd.Object,
-							},
-						},
-						Definitions: map[string]smd.Definition{
-							"Address": {
-								Type: "object",
-								Properties: map[string]smd.Property{
-									"Street": {
-										Description: ``,
-										Type:        smd.String,
-									},
-									"City": {
-										Description: ``,
-										Type:        smd.String,
-									},
-								},
-							},
-						},
+						Ref:         "#/definitions/testdata.Person",
 					},
 					{
 						Name:        "replace",
 						Optional:    true,
+						Default:     smd.Default[bool](false),
 						Description: `update person if exist`,
 						Type:        smd.Boolean,
 					},
@@ -1231,8 +1673,9 @@ func (PhoneBook) SMD() smd.ServiceInfo {
 				Description: `Prints message`,
 				Parameters: []smd.JSONSchema{
 					{
-						Name:        "str",
+						Name:        "type",
 						Optional:    true,
+						Default:     smd.Default[string]("hello world"),
 						Description: ``,
 						Type:        smd.String,
 					},
@@ -1244,9 +1687,113 @@ func (PhoneBook) SMD() smd.ServiceInfo {
 				},
 			},
 		},
+		Definitions: map[string]smd.Definition{
+			"testdata.PersonSearch": {
+				Type: smd.Object,
+				Properties: map[string]smd.Property{
+					"ByName": {
+						Description: `ByName is filter for searching person by first name or last name.`,
+						Nullable:    true,
+						Type:        smd.String,
+					},
+					"ByType": {
+						Description: ``,
+						Nullable:    true,
+						Type:        smd.String,
+						Constraints: smd.Constraints{
+							Enum: []interface{}{"mobile", "work"},
+						},
+					},
+					"ByPhone": {
+						Description: ``,
+						Type:        smd.String,
+						Constraints: smd.Constraints{
+							MaxLength: 20,
+							Pattern:   "^\\+?[0-9-]*$",
+						},
+					},
+					"ByAddress": {
+						Description: ``,
+						Nullable:    true,
+						Type:        smd.Object,
+						Ref:         "#/definitions/testdata.Address",
+					},
+				},
+				Required: []string{"ByPhone"},
+			},
+			"testdata.Address": {
+				Type: smd.Object,
+				Properties: map[string]smd.Property{
+					"Street": {
+						Description: ``,
+						Type:        smd.String,
+					},
+					"City": {
+						Description: ``,
+						Type:        smd.String,
+					},
+				},
+				Required: []string{"Street", "City"},
+			},
+			"testdata.Person": {
+				Type: smd.Object,
+				Properties: map[string]smd.Property{
+					"ID": {
+						Description: `ID is Unique Identifier for person`,
+						Type:        smd.Integer,
+					},
+					"FirstName": {
+						Description: ``,
+						Type:        smd.String,
+					},
+					"LastName": {
+						Description: ``,
+						Type:        smd.String,
+					},
+					"Phone": {
+						Description: `Phone is main phone`,
+						Type:        smd.String,
+					},
+					"WorkPhone": {
+						Description: ``,
+						Nullable:    true,
+						Type:        smd.String,
+					},
+					"Mobile": {
+						Description: ``,
+						Type:        smd.Array,
+						Items: &smd.Property{
+							Type: smd.String,
+						},
+					},
+					"Deleted": {
+						Description: `Deleted is flag for`,
+						Type:        smd.Boolean,
+					},
+					"Addresses": {
+						Description: `Addresses Could be nil or len() == 0.`,
+						Type:        smd.Array,
+						Items: &smd.Property{
+							Type: smd.Object,
+							Ref:  "#/definitions/testdata.Address",
+						},
+					},
+					"address": {
+						Description: ``,
+						Nullable:    true,
+						Type:        smd.Object,
+						Ref:         "#/definitions/testdata.Address",
+					},
+				},
+				Required: []string{"ID", "FirstName", "LastName", "Phone", "Mobile", "Deleted", "Addresses"},
+			},
+		},
 	}
 }
 
+// smdPhoneBook is used for params constraints validation.
+var smdPhoneBook = PhoneBook{}.SMD()
+
 // Invoke is as generated code from zenrpc cmd
 func (s PhoneBook) Invoke(ctx context.Context, method string, params json.RawMessage) zenrpc.Response {
 	resp := zenrpc.Response{}
@@ -1254,6 +1801,10 @@ func (s PhoneBook) Invoke(ctx context.Context, method string, params json.RawMes
 
 	switch method {
 	case RPC.PhoneBook.Get:
+		if errs := smdPhoneBook.Methods["Get"].ValidateConstraints(params, smdPhoneBook.Definitions); len(errs) > 0 {
+			return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", errs)
+		}
+
 		var args = struct {
 			Search PersonSearch `json:"search"`
 			Page   *int         `json:"page"`
@@ -1272,13 +1823,13 @@ func (s PhoneBook) Invoke(ctx context.Context, method string, params json.RawMes
 			}
 		}
 
-		//zenrpc:count=50 page size
+		//zenrpc:count=50 min=1 max=100 page size
 		if args.Count == nil {
 			var v int = 50
 			args.Count = &v
 		}
 
-		//zenrpc:page=0 current page
+		//zenrpc:page=0 min=0 current page
 		if args.Page == nil {
 			var v int = 0
 			args.Page = &v
@@ -1287,6 +1838,10 @@ func (s PhoneBook) Invoke(ctx context.Context, method string, params json.RawMes
 		resp.Set(s.Get(args.Search, args.Page, args.Count))
 
 	case RPC.PhoneBook.ValidateSearch:
+		if errs := smdPhoneBook.Methods["ValidateSearch"].ValidateConstraints(params, smdPhoneBook.Definitions); len(errs) > 0 {
+			return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", errs)
+		}
+
 		var args = struct {
 			Search *PersonSearch `json:"search"`
 		}{}
@@ -1307,7 +1862,7 @@ func (s PhoneBook) Invoke(ctx context.Context, method string, params json.RawMes
 
 	case RPC.PhoneBook.ById:
 		var args = struct {
-			Id uint64 `json:"id"`
+			Id PersonID `json:"id"`
 		}{}
 
 		if zenrpc.IsArray(params) {
@@ -1430,6 +1985,7 @@ func (PrintService) SMD() smd.ServiceInfo {
 					{
 						Name:        "s",
 						Optional:    true,
+						Default:     smd.Default[string]("test"),
 						Description: ``,
 						Type:        smd.String,
 					},
@@ -1446,6 +2002,7 @@ func (PrintService) SMD() smd.ServiceInfo {
 					{
 						Name:        "s",
 						Optional:    true,
+						Default:     smd.Default[string]("test"),
 						Description: ``,
 						Type:        smd.String,
 					},
@@ -0,0 +1,92 @@
+package zenrpc
+
+import (
+	"context"
+)
+
+// context key for transaction.
+const txKey contextKey = "tx"
+
+// Tx is a transaction handle for transactional batches.
+type Tx interface {
+	Commit(ctx context.Context) error
+	Rollback(ctx context.Context) error
+}
+
+// TxBeginFunc is a function for starting new transaction for transactional batch.
+type TxBeginFunc func(ctx context.Context) (Tx, error)
+
+// processTxBatch process batch requests sequentially in single transaction.
+// If any request fails then transaction is rolled back and all responses are marked as failed.
+func (s Server) processTxBatch(ctx context.Context, requests []Request) []Response {
+	tx, err := s.options.BeginTx(ctx)
+	if err != nil {
+		s.printf("begin transaction failed with err=%v", err)
+		return txResponses(requests, nil, NewStringError(ServerError, "transaction begin failed"))
+	}
+
+	// set transaction to context
+	ctx = newTxContext(ctx, tx)
+
+	// running requests one by one until first error
+	responses := make([]Response, 0, len(requests))
+	for _, req := range requests {
+		resp := s.processRequest(ctx, req)
+		responses = append(responses, resp)
+
+		if resp.Error != nil {
+			break
+		}
+	}
+
+	failed := len(responses) < len(requests) || responses[len(responses)-1].Error != nil
+	if !failed {
+		if err := tx.Commit(ctx); err != nil {
+			s.printf("commit transaction failed with err=%v", err)
+			return txResponses(requests, nil, NewStringError(ServerError, "transaction commit failed"))
+		}
+
+		return txResponses(requests, responses, nil)
+	}
+
+	if err := tx.Rollback(ctx); err != nil {
+		s.printf("rollback transaction failed with err=%v", err)
+	}
+
+	return txResponses(requests, responses, NewStringError(ServerError, "transaction rolled back"))
+}
+
+// txResponses collects responses for non notification requests.
+// If failure is set then all successful or not processed requests are replaced with it.
+func txResponses(requests []Request, responses []Response, failure *Error) []Response {
+	result := make([]Response, 0, len(requests))
+	for i, req := range requests {
+		if req.ID == nil {
+			continue
+		}
+
+		if i < len(responses) && (failure == nil || responses[i].Error != nil) {
+			result = append(result, responses[i])
+		} else {
+			result = append(result, NewResponseError(req.ID, failure.Code, failure.Message, nil))
+		}
+	}
+
+	// no responses -> all requests are notifications
+	if len(result) == 0 {
+		return nil
+	}
+
+	return result
+}
+
+// newTxContext creates new context with transaction.
+func newTxContext(ctx context.Context, tx Tx) context.Context {
+	return context.WithValue(ctx, txKey, tx)
+}
+
+// TxFromContext returns transaction from context. It's set only for transactional batches.
+func TxFromContext(ctx context.Context) (Tx, bool) {
+	tx, ok := ctx.Value(txKey).(Tx)
+	return tx, ok
+}
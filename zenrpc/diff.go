@@ -0,0 +1,150 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// diffContext is number of unchanged lines around changes in unified diff.
+const diffContext = 3
+
+// edit is line of diff: unchanged ' ', deleted '-' or inserted '+'.
+type edit struct {
+	kind byte
+	line string
+}
+
+// unifiedDiff returns unified diff from a to b, empty if they are equal.
+func unifiedDiff(aName, bName string, a, b []byte) string {
+	if bytes.Equal(a, b) {
+		return ""
+	}
+
+	edits := diffLines(splitLines(a), splitLines(b))
+
+	out := &strings.Builder{}
+	fmt.Fprintf(out, "--- %s\n+++ %s\n", aName, bName)
+
+	// line numbers of a and b before edits[i]
+	aLines, bLines := make([]int, len(edits)+1), make([]int, len(edits)+1)
+	for i, e := range edits {
+		aLines[i+1], bLines[i+1] = aLines[i], bLines[i]
+		if e.kind != '+' {
+			aLines[i+1]++
+		}
+		if e.kind != '-' {
+			bLines[i+1]++
+		}
+	}
+
+	for i := 0; i < len(edits); {
+		if edits[i].kind == ' ' {
+			i++
+			continue
+		}
+
+		// hunk includes changes separated by at most two contexts of unchanged lines
+		start, end := max(i-diffContext, 0), i
+		for j := i; j < len(edits) && j <= end+2*diffContext+1; j++ {
+			if edits[j].kind != ' ' {
+				end = j
+			}
+		}
+		end = min(end+diffContext+1, len(edits))
+
+		fmt.Fprintf(out, "@@ -%s +%s @@\n", hunkRange(aLines[start], aLines[end]), hunkRange(bLines[start], bLines[end]))
+		for _, e := range edits[start:end] {
+			out.WriteByte(e.kind)
+			out.WriteString(e.line)
+			if !strings.HasSuffix(e.line, "\n") {
+				out.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+
+		i = end
+	}
+
+	return out.String()
+}
+
+// hunkRange returns range of lines [from, to) in unified diff format.
+func hunkRange(from, to int) string {
+	if to-from == 1 {
+		return fmt.Sprint(from + 1)
+	}
+
+	if to == from {
+		return fmt.Sprintf("%d,0", from) // empty range refers to line before it
+	}
+
+	return fmt.Sprintf("%d,%d", from+1, to-from)
+}
+
+// splitLines splits text into lines, each line keeps its trailing newline.
+func splitLines(text []byte) []string {
+	lines := strings.SplitAfter(string(text), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return lines
+}
+
+// diffLines returns edits transforming a into b by longest common subsequence of lines.
+// Common prefix and suffix are trimmed first, because generated code usually changes in few places.
+func diffLines(a, b []string) []edit {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	edits := make([]edit, 0, len(a)+len(b))
+	for _, line := range a[:prefix] {
+		edits = append(edits, edit{' ', line})
+	}
+
+	am, bm := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+
+	// lcs[i][j] is length of longest common subsequence of am[i:] and bm[j:]
+	lcs := make([][]int32, len(am)+1)
+	for i := range lcs {
+		lcs[i] = make([]int32, len(bm)+1)
+	}
+	for i := len(am) - 1; i >= 0; i-- {
+		for j := len(bm) - 1; j >= 0; j-- {
+			if am[i] == bm[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(am) || j < len(bm) {
+		switch {
+		case i < len(am) && j < len(bm) && am[i] == bm[j]:
+			edits = append(edits, edit{' ', am[i]})
+			i++
+			j++
+		case j == len(bm) || i < len(am) && lcs[i+1][j] >= lcs[i][j+1]:
+			edits = append(edits, edit{'-', am[i]})
+			i++
+		default:
+			edits = append(edits, edit{'+', bm[j]})
+			j++
+		}
+	}
+
+	for _, line := range a[len(a)-suffix:] {
+		edits = append(edits, edit{' ', line})
+	}
+
+	return edits
+}
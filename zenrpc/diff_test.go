@@ -0,0 +1,25 @@
+package main
+
+import "testing"
+
+func Test_unifiedDiff(t *testing.T) {
+	tc := []struct {
+		a, b, diff string
+	}{
+		{a: "a\nb\n", b: "a\nb\n", diff: ""},
+		{a: "", b: "a\n", diff: "--- x.orig\n+++ x\n@@ -0,0 +1 @@\n+a\n"},
+		{a: "a\nb\nc\n", b: "a\nc\n", diff: "--- x.orig\n+++ x\n@@ -1,3 +1,2 @@\n a\n-b\n c\n"},
+		{a: "a\n", b: "a", diff: "--- x.orig\n+++ x\n@@ -1 +1 @@\n-a\n+a\n\\ No newline at end of file\n"},
+		{
+			a:    "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n14\n15\n16\n",
+			b:    "1\nX\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n14\nY\n16\n",
+			diff: "--- x.orig\n+++ x\n@@ -1,5 +1,5 @@\n 1\n-2\n+X\n 3\n 4\n 5\n@@ -12,5 +12,5 @@\n 12\n 13\n 14\n-15\n+Y\n 16\n",
+		},
+	}
+
+	for _, c := range tc {
+		if diff := unifiedDiff("x.orig", "x", []byte(c.a), []byte(c.b)); diff != c.diff {
+			t.Errorf("unifiedDiff(%q, %q) = %q, want %q", c.a, c.b, diff, c.diff)
+		}
+	}
+}
@@ -2,10 +2,14 @@ package main
 
 import (
 	"bytes"
+	"errors"
+	"flag"
 	"fmt"
 	"github.com/semrush/zenrpc/v2/parser"
 	"go/format"
 	"os"
+	"strings"
+	"text/template"
 	"time"
 )
 
@@ -17,12 +21,50 @@ const (
 )
 
 func main() {
+	withClient := flag.Bool("client", false, "generate typed client to <pkg>"+parser.GenerateClientFileSuffix)
+	typeMappings := typeMappingsFlag{}
+	flag.Var(typeMappings, "map", "custom SMD type of named type <package path>.<type>=<type>[:<format>], e.g. time.Duration=integer; can be repeated")
+	out := &output{}
+	flag.BoolVar(&out.check, "check", false, "do not write files, exit with non-zero code and print diff if generated code is stale")
+	flag.BoolVar(&out.diff, "diff", false, "do not write files, print diff between generated code and files on disk")
+	flag.BoolVar(&out.verbose, "v", false, "print definitions of generated services")
+	flag.BoolVar(&out.quiet, "q", false, "print errors only")
+	outputFile := flag.String("o", "", "output file, default is <pkg>"+parser.GenerateFileSuffix+" in package directory")
+	services := flag.String("services", "", "comma separated names of services to generate, default is all services")
+	tags := flag.String("tags", "", "comma separated build tags for loading packages")
+	flag.Parse()
+
+	if out.verbose && out.quiet {
+		fmt.Fprintln(os.Stderr, "Flags -v and -q can't be used together")
+		os.Exit(1)
+	}
+
+	opts := options{
+		withClient:   *withClient,
+		typeMappings: typeMappings,
+		outputFile:   *outputFile,
+		services:     splitList(*services),
+		tags:         splitList(*tags),
+	}
+
 	start := time.Now()
-	fmt.Printf("Generator version: %s\n", version)
+	out.printf("Generator version: %s\n", version)
+
+	if patterns := flag.Args(); isPatterns(patterns) {
+		out.printf("Packages: %s\n", strings.Join(patterns, " "))
+		if err := generatePackages(patterns, opts, out); err != nil {
+			printError(err)
+			os.Exit(1)
+		}
+
+		out.printf("Duration: %dms\n", int64(time.Since(start)/time.Millisecond))
+		out.exitIfStale()
+		return
+	}
 
 	var filename string
-	if len(os.Args) > 1 {
-		filename = os.Args[len(os.Args)-1]
+	if flag.NArg() > 0 {
+		filename = flag.Arg(flag.NArg() - 1)
 	} else {
 		filename = os.Getenv("GOFILE")
 	}
@@ -32,22 +74,34 @@ func main() {
 		os.Exit(1)
 	}
 
-	fmt.Printf("Entrypoint: %s\n", filename)
+	out.printf("Entrypoint: %s\n", filename)
 
 	// create package info
-	pi, err := parser.NewPackageInfo(filename)
+	pi, err := parser.NewPackageInfo(filename, opts.tags...)
 	if err != nil {
 		printError(err)
 		os.Exit(1)
 	}
 
-	outputFileName := pi.OutputFilename()
+	opts.apply(pi)
 
-	// remove output file if it already exists
-	if _, err := os.Stat(outputFileName); err == nil {
-		if err := os.Remove(outputFileName); err != nil {
-			printError(err)
-			os.Exit(1)
+	outputFileName, clientFileName := pi.OutputFilename(), pi.ClientOutputFilename()
+	outputFileNames := []string{outputFileName}
+	if opts.withClient {
+		outputFileNames = append(outputFileNames, clientFileName)
+	}
+
+	// remove output files if they already exist, they are compared with generated code in check mode
+	for _, name := range outputFileNames {
+		if out.check || out.diff {
+			break
+		}
+
+		if _, err := os.Stat(name); err == nil {
+			if err := os.Remove(name); err != nil {
+				printError(err)
+				os.Exit(1)
+			}
 		}
 	}
 
@@ -56,21 +110,156 @@ func main() {
 		os.Exit(1)
 	}
 
+	if err := opts.checkServices([]*parser.PackageInfo{pi}); err != nil {
+		printError(err)
+		os.Exit(1)
+	}
+
 	if len(pi.Services) == 0 {
 		fmt.Fprintln(os.Stderr, "Services not found")
 		os.Exit(1)
 	}
 
-	if err := generateFile(outputFileName, pi); err != nil {
+	if err := generateFiles(pi, opts.withClient, out); err != nil {
 		printError(err)
 		os.Exit(1)
 	}
 
-	fmt.Printf("Generated: %s\n", outputFileName)
-	fmt.Printf("Duration: %dms\n", int64(time.Since(start)/time.Millisecond))
-	fmt.Println()
-	fmt.Print(pi)
-	fmt.Println()
+	out.printf("Duration: %dms\n", int64(time.Since(start)/time.Millisecond))
+	out.summary(pi)
+	out.exitIfStale()
+}
+
+// options are generator flags applied to each package.
+type options struct {
+	withClient   bool
+	typeMappings typeMappingsFlag
+	outputFile   string
+	services     []string
+	tags         []string
+}
+
+func (o options) apply(pi *parser.PackageInfo) {
+	for name, t := range o.typeMappings {
+		pi.TypeMappings[name] = t
+	}
+
+	pi.OutputFile = o.outputFile
+	pi.ServiceNames = o.services
+}
+
+// checkServices checks that all services from -services flag are found in parsed packages.
+func (o options) checkServices(pis []*parser.PackageInfo) error {
+	var missing []string
+	for _, name := range o.services {
+		found := false
+		for _, pi := range pis {
+			for _, s := range pi.Services {
+				found = found || s.Name == name
+			}
+		}
+
+		if !found {
+			missing = append(missing, name)
+		}
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("services not found: %s", strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
+// splitList splits comma separated flag value.
+func splitList(value string) []string {
+	var result []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			result = append(result, v)
+		}
+	}
+
+	return result
+}
+
+// isPatterns checks that arguments are package patterns or directories, e.g. ./..., instead of entry point file.
+func isPatterns(args []string) bool {
+	for _, arg := range args {
+		if strings.Contains(arg, "...") {
+			return true
+		}
+
+		if fi, err := os.Stat(arg); err == nil && fi.IsDir() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// generatePackages generates code for all packages with services matching patterns.
+// Packages are loaded in single pass, errors of all packages are returned together.
+func generatePackages(patterns []string, opts options, out *output) error {
+	pis, err := parser.LoadPackages(opts.tags, patterns...)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	var parsed []*parser.PackageInfo
+	for _, pi := range pis {
+		opts.apply(pi)
+		if err := pi.ParsePackage(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", pi.PackagePath, err))
+			continue
+		}
+
+		if len(pi.Services) > 0 {
+			parsed = append(parsed, pi)
+		}
+	}
+
+	// services of packages with errors are unknown, packages without errors are generated anyway
+	if len(errs) == 0 {
+		if err := opts.checkServices(parsed); err != nil {
+			return err
+		}
+
+		if len(parsed) == 0 {
+			return errors.New("services not found")
+		}
+	}
+
+	if opts.outputFile != "" && len(parsed) > 1 {
+		return fmt.Errorf("output file %s can't be used for %d packages", opts.outputFile, len(parsed))
+	}
+
+	for _, pi := range parsed {
+		if err := generateFiles(pi, opts.withClient, out); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", pi.PackagePath, err))
+			continue
+		}
+
+		out.summary(pi)
+	}
+
+	return errors.Join(errs...)
+}
+
+// generateFiles writes server and optionally client code of parsed package.
+func generateFiles(pi *parser.PackageInfo, withClient bool, out *output) error {
+	if err := generateFile(pi.OutputFilename(), serviceTemplate, pi, out); err != nil {
+		return err
+	}
+
+	if withClient {
+		if err := generateFile(pi.ClientOutputFilename(), clientTemplate, pi, out); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 func printError(err error) {
@@ -84,15 +273,9 @@ func printError(err error) {
 	fmt.Printf("\t%s\n\n", githubURL)
 }
 
-func generateFile(outputFileName string, pi *parser.PackageInfo) error {
-	file, err := os.Create(outputFileName)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
-
+func generateFile(outputFileName string, tmpl *template.Template, pi *parser.PackageInfo, out *output) error {
 	output := new(bytes.Buffer)
-	if err := serviceTemplate.Execute(output, pi); err != nil {
+	if err := tmpl.Execute(output, pi); err != nil {
 		return err
 	}
 
@@ -101,9 +284,93 @@ func generateFile(outputFileName string, pi *parser.PackageInfo) error {
 		return err
 	}
 
-	if _, err = file.Write(source); err != nil {
+	return out.write(outputFileName, source)
+}
+
+// output writes generated files or compares them with files on disk in -check and -diff modes.
+// It also prints generator messages with verbosity set by -v and -q flags.
+type output struct {
+	check, diff    bool
+	verbose, quiet bool
+	stale          []string // files which differ from generated code
+}
+
+// printf prints informational message, it is suppressed in quiet mode.
+func (o *output) printf(format string, args ...interface{}) {
+	if !o.quiet {
+		fmt.Printf(format, args...)
+	}
+}
+
+// summary prints generated services of package and definitions used by them in verbose mode.
+func (o *output) summary(pi *parser.PackageInfo) {
+	if o.quiet {
+		return
+	}
+
+	fmt.Println()
+	fmt.Print(pi)
+	if o.verbose {
+		for _, s := range pi.Services {
+			var names []string
+			for _, d := range parser.Definitions(s, pi.Structs) {
+				names = append(names, d.Name)
+			}
+
+			if len(names) > 0 {
+				fmt.Printf("Definitions of %s: %s\n", s.Name, strings.Join(names, ", "))
+			}
+		}
+	}
+	fmt.Println()
+}
+
+func (o *output) write(filename string, source []byte) error {
+	if !o.check && !o.diff {
+		if err := os.WriteFile(filename, source, 0644); err != nil {
+			return err
+		}
+
+		o.printf("Generated: %s\n", filename)
+		return nil
+	}
+
+	current, err := os.ReadFile(filename)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	if diff := unifiedDiff(filename+".orig", filename, current, source); diff != "" {
+		o.stale = append(o.stale, filename)
+		fmt.Print(diff)
+	}
+
+	return nil
+}
+
+// exitIfStale exits with non-zero code in check mode if any generated file is stale.
+func (o *output) exitIfStale() {
+	if !o.check || len(o.stale) == 0 {
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "Generated code is stale, run zenrpc to update:\n\t%s\n", strings.Join(o.stale, "\n\t"))
+	os.Exit(1)
+}
+
+// typeMappingsFlag collects repeated -map flags.
+type typeMappingsFlag map[string]parser.SMDType
+
+func (f typeMappingsFlag) String() string {
+	return ""
+}
+
+func (f typeMappingsFlag) Set(value string) error {
+	name, t, err := parser.ParseTypeMapping(value)
+	if err != nil {
 		return err
 	}
 
+	f[name] = t
 	return nil
 }
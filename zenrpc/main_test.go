@@ -0,0 +1,29 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_generatePackages(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "subarithservice_zenrpc.go")
+	opts := options{outputFile: filename}
+	patterns := []string{"../parser/testdata/broken", "../testdata/subservice", "../parser/testdata/results"}
+
+	err := generatePackages(patterns, opts, &output{quiet: true})
+	if err == nil {
+		t.Fatal("generatePackages() error = nil, want errors of broken packages")
+	}
+
+	for _, want := range []string{"undefined: Undefinedd", "return argument A of ResultsService.Get conflicts"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("generatePackages() error = %v, want %q", err, want)
+		}
+	}
+
+	if _, err := os.Stat(filename); err != nil {
+		t.Errorf("package without errors is not generated: %v", err)
+	}
+}
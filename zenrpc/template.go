@@ -2,38 +2,91 @@ package main
 
 import (
 	"github.com/semrush/zenrpc/v2/parser"
+	"strings"
 	"text/template"
 )
 
 var (
 	serviceTemplate = template.Must(template.New("service").
-		Funcs(template.FuncMap{"definitions": parser.Definitions}).
+		Funcs(template.FuncMap{"definitions": parser.Definitions, "hasConstraints": parser.HasConstraints}).
 		Parse(`
 {{define "smdType" -}}
 	Type: smd.{{.Type}},
-	{{- if eq .Type "Array" }}
-		Items: map[string]string{
-			{{- if and (eq .ItemsType "Object") .Ref }}
-			"$ref": "#/definitions/{{.Ref}}",
-			{{else}}
-			"type": smd.{{.ItemsType}},
-			{{end}}
+	{{- if .Format }}
+		Format: {{printf "%q" .Format}},
+	{{- end}}
+	{{- if .ContentEncoding }}
+		ContentEncoding: {{printf "%q" .ContentEncoding}},
+	{{- end}}
+	{{- if .Ref }}
+		Ref: "#/definitions/{{.Ref}}",
+	{{- end}}
+	{{- if .Values }}
+		AdditionalProperties: &smd.Property{
+			{{template "smdType" .Values}}
+		},
+	{{- end}}
+	{{- if .Items }}
+		Items: &smd.Property{
+			{{template "smdType" .Items}}
+		},
+	{{- end}}
+	{{- if .OneOf }}
+		OneOf: []smd.Property{
+			{{- range .OneOf }}
+				{
+					{{template "smdType" .}}
+				},
+			{{- end }}
+		},
+	{{- end}}
+	{{- if or (not .Constraints.IsEmpty) .Enum }}
+		Constraints: smd.Constraints{
+			{{- with .Constraints }}
+				{{- if .Minimum }}
+					Minimum: smd.Number({{.Minimum}}),
+				{{- end}}
+				{{- if .Maximum }}
+					Maximum: smd.Number({{.Maximum}}),
+				{{- end}}
+				{{- if .MinLength }}
+					MinLength: {{.MinLength}},
+				{{- end}}
+				{{- if .MaxLength }}
+					MaxLength: {{.MaxLength}},
+				{{- end}}
+				{{- if .MinItems }}
+					MinItems: {{.MinItems}},
+				{{- end}}
+				{{- if .MaxItems }}
+					MaxItems: {{.MaxItems}},
+				{{- end}}
+				{{- if .Pattern }}
+					Pattern: {{printf "%q" .Pattern}},
+				{{- end}}
+			{{- end}}
+			{{- with .EnumLiterals }}
+				Enum: []interface{}{ {{- range $i, $e := . }}{{if $i}}, {{end}}{{.}}{{ end -}} },
+			{{- end}}
 		},
 	{{- end}}
 {{- end}}
 
 {{define "properties" -}}
 	Properties: map[string]smd.Property{
-	{{range $i, $e := . -}}
+	{{range $i, $e := .Properties -}}
 		"{{.Name}}": {
 			Description: ` + "`{{.Description}}`" + `,
-			{{- if and (eq .SMDType.Type "Object") .SMDType.Ref }}
-				Ref: "#/definitions/{{.SMDType.Ref}}",
-			{{- end}}			
+			{{- if .Nullable }}
+				Nullable: true,
+			{{- end}}
 			{{template "smdType" .SMDType}}
 		},
 	{{ end }}
 	},
+	{{- with .Required }}
+		Required: []string{ {{- range $i, $e := . }}{{if $i}}, {{end}}"{{.}}"{{ end -}} },
+	{{- end}}
 {{- end}}
 
 {{define "definitions" -}}
@@ -41,8 +94,8 @@ var (
 	Definitions: map[string]smd.Definition{
 		{{- range .}}
 			"{{ .Name }}": {
-				Type: "object",
-				{{ template "properties" .Properties}}
+				Type: smd.Object,
+				{{ template "properties" .}}
 			},
 		{{- end }}
 	},
@@ -81,25 +134,36 @@ var RPC = struct {
 }
 
 {{ range $s := .Services}}
+	{{- if .Interface }}
+
+	// {{.InvokerName}} is zenrpc.Invoker adapter for {{.Name}} implementation.
+	type {{.InvokerName}} struct {
+		{{.Name}}
+	}
+
+	// New{{.InvokerName}} returns zenrpc.Invoker for given {{.Name}} implementation.
+	func New{{.InvokerName}}(impl {{.Name}}) {{.InvokerName}} {
+		return {{.InvokerName}}{impl}
+	}
+	{{- end }}
 
-	func ({{.Name}}) SMD() smd.ServiceInfo {
+	func ({{.InvokerName}}) SMD() smd.ServiceInfo {
 		return smd.ServiceInfo{
 			Description: ` + "`{{.Description}}`" + `,
 			Methods: map[string]smd.Service{ 
-				{{- range .Methods }}
+				{{- range $m := .Methods }}
 					"{{.Name}}": {
 						Description: ` + "`{{.Description}}`" + `,
 						Parameters: []smd.JSONSchema{ 
 						{{- range .Args }}
 							{
-								Name: "{{.Name}}",
-								Optional: {{or .HasStar .HasDefaultValue}},
+								Name: "{{.JsonName}}",
+								Optional: {{or .HasStar .HasDefaultValue .Variadic}},
+								{{- if .HasDefaultValue }}{{ with index $m.DefaultValues .Name }}
+								Default: smd.Default[{{.Type}}]({{.Value}}),
+								{{- end }}{{ end }}
 								Description: ` + "`{{.Description}}`" + `,
 								{{template "smdType" .SMDType}}
-								{{- if and (eq .SMDType.Type "Object") (ne .SMDType.Ref "")}}
-									{{ template "properties" (index $.Structs .SMDType.Ref).Properties}}
-								{{- end}}
-								{{- template "definitions" definitions .SMDType $.Structs }}
 							},
 						{{- end }}
 						}, 
@@ -108,11 +172,9 @@ var RPC = struct {
 								Description: ` + "`{{.SMDReturn.Description}}`" + `,
 								Optional:    {{.SMDReturn.HasStar}},
 								{{template "smdType" .SMDReturn.SMDType }}
-								{{- if and (eq .SMDReturn.SMDType.Type "Object") (ne .SMDReturn.SMDType.Ref "")}}
-									{{ template "properties" (index $.Structs .SMDReturn.SMDType.Ref).Properties}}
-								{{- end}}
-								{{- template "definitions" definitions .SMDReturn.SMDType $.Structs }}							
 							}, 
+						{{- else}}
+							Returns: smd.JSONSchema{Type: smd.Null},
 						{{- end}}
 						{{- if .Errors}}
 							Errors: map[int]string{
@@ -124,17 +186,32 @@ var RPC = struct {
 					}, 
 				{{- end }}
 			},
+			{{- template "definitions" definitions $s $.Structs }}
 		}
 	}
 
+	{{- $hasConstraints := false }}
+	{{- range .Methods }}{{ if hasConstraints . $.Structs }}{{ $hasConstraints = true }}{{ end }}{{ end }}
+	{{- if $hasConstraints }}
+
+	// smd{{.Name}} is used for params constraints validation.
+	var smd{{.Name}} = {{.InvokerName}}{}.SMD()
+	{{- end }}
+
 	// Invoke is as generated code from zenrpc cmd
-	func (s {{.Name}}) Invoke(ctx context.Context, method string, params json.RawMessage) zenrpc.Response {
+	func (s {{.InvokerName}}) Invoke(ctx context.Context, method string, params json.RawMessage) zenrpc.Response {
 		resp := zenrpc.Response{}
 		{{ if .HasErrorVariable }}var err error{{ end }}
 
 		switch method { 
 		{{- range .Methods }}
-			case RPC.{{$s.Name}}.{{.Name}}: {{ if .Args }}
+			case RPC.{{$s.Name}}.{{.Name}}: 
+				{{- if hasConstraints . $.Structs }}
+					if errs := smd{{$s.Name}}.Methods["{{.Name}}"].ValidateConstraints(params, smd{{$s.Name}}.Definitions); len(errs) > 0 {
+						return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", errs)
+					}
+				{{ end }}
+				{{- if .Args }}
 					var args = struct {
 						{{ range .Args }}
 							{{.CapitalName}} {{if and (not .HasStar) .HasDefaultValue}}*{{end}}{{.Type}} ` + "`json:\"{{.JsonName}}\"`" + `
@@ -163,10 +240,18 @@ var RPC = struct {
 						}
 					{{ end }}
 
-				{{ end }} {{if .Returns}}
-					resp.Set(s.{{.Name}}({{if .HasContext}}ctx, {{end}} {{ range .Args }}{{if and (not .HasStar) .HasDefaultValue}}*{{end}}args.{{.CapitalName}}, {{ end }}))
+				{{ end }} {{if .ResultStruct}}
+					{{ range $i, $e := .Returns }}{{if $i}}, {{end}}zr{{$i}}{{ end }} := s.{{.Name}}({{if .HasContext}}ctx, {{end}} {{ range .Args }}{{if and (not .HasStar) .HasDefaultValue}}*{{end}}args.{{.CapitalName}}{{if .Variadic}}...{{end}}, {{ end }})
+					resp.Set(struct {
+						{{- range .Returns }}{{ if not .IsError }}
+							{{.CapitalName}} interface{} ` + "`json:\"{{.Name}}\"`" + `
+						{{- end }}{{ end }}
+					}{ {{- range $i, $e := .Returns }}{{ if not .IsError }}zr{{$i}}, {{ end }}{{ end -}} }
+					{{- range $i, $e := .Returns }}{{ if .IsError }}, zr{{$i}}{{ end }}{{ end }})
+				{{else if .Returns}}
+					resp.Set(s.{{.Name}}({{if .HasContext}}ctx, {{end}} {{ range .Args }}{{if and (not .HasStar) .HasDefaultValue}}*{{end}}args.{{.CapitalName}}{{if .Variadic}}...{{end}}, {{ end }}))
 				{{else}}
-					s.{{.Name}}({{if .HasContext}}ctx, {{end}} {{ range .Args }}{{if and (not .HasStar) .HasDefaultValue}}*{{end}}args.{{.CapitalName}}, {{ end }})
+					s.{{.Name}}({{if .HasContext}}ctx, {{end}} {{ range .Args }}{{if and (not .HasStar) .HasDefaultValue}}*{{end}}args.{{.CapitalName}}{{if .Variadic}}...{{end}}, {{ end }})
 				{{end}}
 		{{- end }}
 		default:
@@ -178,3 +263,84 @@ var RPC = struct {
 {{- end }}
 `))
 )
+
+var (
+	clientTemplate = template.Must(template.New("client").
+		Funcs(template.FuncMap{"comment": comment}).
+		Parse(`
+// Code generated by zenrpc; DO NOT EDIT.
+
+package {{.PackageName}}
+
+import (
+	"context"
+
+	"github.com/semrush/zenrpc/v2/client"
+
+	{{ range .ImportsIncludedToGeneratedClient}}
+		{{if .Name}}{{.Name.Name}} {{end}}{{.Path.Value}}
+	{{- end }}
+)
+
+{{ range $s := .Services}}
+	// {{.Name}}Client is a typed JSON-RPC 2.0 client for {{.Name}}.
+	type {{.Name}}Client struct {
+		client    *client.Client
+		namespace string
+	}
+
+	// New{{.Name}}Client returns new typed client for {{.Name}} registered with given namespace.
+	func New{{.Name}}Client(c *client.Client, namespace string) *{{.Name}}Client {
+		return &{{.Name}}Client{client: c, namespace: namespace}
+	}
+
+	// method returns method name with namespace.
+	func (zc *{{.Name}}Client) method(name string) string {
+		if zc.namespace == "" {
+			return name
+		}
+
+		return zc.namespace + "." + name
+	}
+	{{- if .ClientImplements }}
+
+	// {{.Name}}Client implements {{.Name}} interface.
+	var _ {{.Name}} = (*{{.Name}}Client)(nil)
+	{{- end }}
+
+	{{ range .Methods }}
+		{{- if .Description}}{{comment .Description}}{{else}}// {{.Name}} calls {{$s.Name}}.{{.Name}} method.{{end}}
+		func (zc *{{$s.Name}}Client) {{.Name}}(ctx context.Context{{ range .Args }}, {{.Name}} {{if .Variadic}}...{{slice .Type 2}}{{else}}{{.Type}}{{end}}{{ end }}) {{if .ResultStruct}}({{ range .Returns }}{{ if not .IsError }}{{.Type}}, {{ end }}{{ end }}error){{else if .ClientReturn}}({{.ClientReturn}}, error){{else}}error{{end}} {
+			{{- if .ResultStruct}}
+				var zres struct {
+					{{- range .Returns }}{{ if not .IsError }}
+						{{.CapitalName}} {{.Type}} ` + "`json:\"{{.Name}}\"`" + `
+					{{- end }}{{ end }}
+				}
+				err := zc.client.Call(ctx, zc.method(RPC.{{$s.Name}}.{{.Name}}), {{template "params" .}}, &zres)
+				return {{ range .Returns }}{{ if not .IsError }}zres.{{.CapitalName}}, {{ end }}{{ end }}err
+			{{- else if .ClientReturn}}
+				var zres {{.ClientReturn}}
+				err := zc.client.Call(ctx, zc.method(RPC.{{$s.Name}}.{{.Name}}), {{template "params" .}}, &zres)
+				return zres, err
+			{{- else}}
+				return zc.client.Call(ctx, zc.method(RPC.{{$s.Name}}.{{.Name}}), {{template "params" .}}, nil)
+			{{- end}}
+		}
+	{{ end }}
+{{- end }}
+
+{{define "params" -}}
+	{{- if .Args -}}
+		map[string]interface{}{ {{- range $i, $e := .Args }}{{if $i}}, {{end}}"{{.JsonName}}": {{.Name}}{{ end -}} }
+	{{- else -}}
+		nil
+	{{- end -}}
+{{- end}}
+`))
+)
+
+// comment formats text as Go comment.
+func comment(text string) string {
+	return "// " + strings.Replace(text, "\n", "\n// ", -1)
+}